@@ -0,0 +1,265 @@
+package gophplib
+
+import (
+	"math"
+
+	"github.com/elliotchance/orderedmap/v2"
+)
+
+// ErrIllegalOffset is returned when an array, an object or any other value of
+// unsupported type is used as a key of Array. It matches ErrTypeError, since
+// PHP 8 throws TypeError.
+var ErrIllegalOffset error = &TypeError{Message: "Illegal offset type"}
+
+// ErrNextElementOccupied is returned when appending an element to an Array
+// whose next integer key would overflow. It matches ErrError, since PHP 8
+// throws Error.
+var ErrNextElementOccupied error = &Error{Message: "Cannot add element to the array as the next element is already occupied"}
+
+// Array is an ordered map which behaves like PHP's array. It preserves the
+// insertion order of keys, and maintains the next integer key to be used by
+// Append, which is nNextFreeElement of PHP's HashTable.
+//
+// Keys of Array are either int or string, and they are normalized like PHP
+// does:
+//   - Decimal integer strings like "123" and "-1" are converted to int. Strings
+//     like "0123", "+1" or "1.5" remain strings.
+//   - int8, int16, int32 and int64 are converted to int.
+//   - Floats are truncated to int. (ex: 1.7 becomes 1)
+//   - bool is converted to int. (true becomes 1, false becomes 0)
+//   - nil is converted to an empty string.
+//   - Resources are converted to their resource IDs with ConvertToLong.
+//
+// Any other types of keys are illegal, and Set returns ErrIllegalOffset for
+// them.
+//
+// Values of Array can be any type which is supported by this package. Nested
+// arrays should be stored as *Array. The zero value of Array is an empty
+// array ready to use, but it should be passed around as *Array since copying
+// an Array value shares its content. Use Copy to get an independent copy like
+// PHP's array assignment does.
+//
+// Array can be used anywhere an array is accepted in this package, such as
+// Implode, ConvertToString, Compare and Identical.
+//
+// References:
+//   - https://github.com/php/php-src/blob/php-5.6.40/Zend/zend_hash.c
+//   - https://github.com/php/php-src/blob/php-5.6.40/Zend/zend_execute.c
+//   - https://www.php.net/manual/en/language.types.array.php
+type Array struct {
+	next int
+	d    *orderedmap.OrderedMap[any, any]
+}
+
+// NewArray returns a new empty Array.
+func NewArray() *Array {
+	return &Array{}
+}
+
+// NewArrayFrom returns a new Array which has the elements of the given array,
+// slice, map or orderedmap.OrderedMap in their order. Keys are normalized like
+// Set does, and nested arrays are converted to *Array as well.
+//
+// Go maps have no order, so the order of elements from them is not
+// guaranteed.
+func NewArrayFrom(source any) (*Array, error) {
+	arr := NewArray()
+	for _, e := range aggregateEntries(source) {
+		value := e.value
+		if isCollectionType(value) {
+			sub, err := NewArrayFrom(value)
+			if err != nil {
+				return nil, err
+			}
+			value = sub
+		}
+		if err := arr.Set(e.key, value); err != nil {
+			return nil, err
+		}
+	}
+	return arr, nil
+}
+
+func (a *Array) init() {
+	if a.d == nil {
+		a.d = orderedmap.NewOrderedMap[any, any]()
+	}
+}
+
+// Len returns the number of elements in the array.
+func (a *Array) Len() int {
+	if a.d == nil {
+		return 0
+	}
+	return a.d.Len()
+}
+
+// Get returns the value of the given key, and whether the key exists. The key
+// is normalized before lookup, so Get("1") and Get(1.5) both return the value
+// of key 1. It returns false for illegal keys.
+func (a *Array) Get(key any) (any, bool) {
+	k, err := normalizeArrayKey(key)
+	if err != nil || a.d == nil {
+		return nil, false
+	}
+	return a.d.Get(k)
+}
+
+// Set sets the value of the given key. If the key already exists, its value is
+// replaced and its position is kept. Otherwise, the key is appended to the end
+// of the array.
+//
+// It returns ErrIllegalOffset if the key is not one of the types described in
+// the documentation of Array.
+func (a *Array) Set(key, value any) error {
+	k, err := normalizeArrayKey(key)
+	if err != nil {
+		return err
+	}
+	a.set(k, value)
+	return nil
+}
+
+// set sets the value of the given normalized key.
+func (a *Array) set(key, value any) {
+	a.init()
+	if n, ok := key.(int); ok && n >= a.next {
+		if n == math.MaxInt {
+			a.next = n
+		} else {
+			a.next = n + 1
+		}
+	}
+	a.d.Set(key, value)
+}
+
+// Append appends the given value with the next integer key, which is one
+// greater than the largest integer key ever used in the array, or 0 if no
+// non-negative integer key has been used. This is the same as PHP's $a[] =
+// $value.
+//
+// It returns ErrNextElementOccupied if the next integer key is already used,
+// which happens only when math.MaxInt has been used as a key.
+func (a *Array) Append(value any) error {
+	a.init()
+	if _, ok := a.d.Get(a.next); ok {
+		return ErrNextElementOccupied
+	}
+	a.set(a.next, value)
+	return nil
+}
+
+// Delete removes the given key from the array, and reports whether the key
+// existed. Like PHP's unset, it does not change the next integer key used by
+// Append.
+func (a *Array) Delete(key any) bool {
+	k, err := normalizeArrayKey(key)
+	if err != nil || a.d == nil {
+		return false
+	}
+	return a.d.Delete(k)
+}
+
+// Keys returns the keys of the array in order. Each key is either int or
+// string.
+func (a *Array) Keys() []any {
+	if a.d == nil {
+		return []any{}
+	}
+	return a.d.Keys()
+}
+
+// Values returns the values of the array in order.
+func (a *Array) Values() []any {
+	values := make([]any, 0, a.Len())
+	a.Range(func(_, value any) bool {
+		values = append(values, value)
+		return true
+	})
+	return values
+}
+
+// Range calls f for each key and value in the array in order. If f returns
+// false, Range stops the iteration.
+func (a *Array) Range(f func(key, value any) bool) {
+	if a.d == nil {
+		return
+	}
+	for el := a.d.Front(); el != nil; el = el.Next() {
+		if !f(el.Key, el.Value) {
+			return
+		}
+	}
+}
+
+// Copy returns a deep copy of the array, including its next integer key.
+// Nested *Array values are copied recursively, and other values are copied
+// shallowly.
+func (a *Array) Copy() *Array {
+	ret := &Array{}
+	a.Range(func(key, value any) bool {
+		if sub, ok := value.(*Array); ok {
+			value = sub.Copy()
+		}
+		ret.set(key, value)
+		return true
+	})
+	ret.next = a.next
+	return ret
+}
+
+// ToOrderedMap converts the array into an orderedmap.OrderedMap. Nested *Array
+// values are converted into orderedmap.OrderedMap as well.
+func (a *Array) ToOrderedMap() orderedmap.OrderedMap[any, any] {
+	ret := *orderedmap.NewOrderedMap[any, any]()
+	a.Range(func(key, value any) bool {
+		if sub, ok := value.(*Array); ok {
+			ret.Set(key, sub.ToOrderedMap())
+		} else {
+			ret.Set(key, value)
+		}
+		return true
+	})
+	return ret
+}
+
+// normalizeArrayKey converts the given key into either int or string, like
+// PHP does for array offsets.
+//
+// Reference:
+//   - https://github.com/php/php-src/blob/php-5.6.40/Zend/zend_execute.c
+func normalizeArrayKey(key any) (any, error) {
+	switch k := key.(type) {
+	case nil:
+		return "", nil
+	case bool:
+		if k {
+			return 1, nil
+		}
+		return 0, nil
+	case string:
+		return phpNumericOrString([]byte(k)), nil
+	case int, int8, int16, int32, int64:
+		return int(longOf(k)), nil
+	case float32:
+		return int(zendDvalToLval(float64(k))), nil
+	case float64:
+		return int(zendDvalToLval(k)), nil
+	}
+	if id, ok := defaultRuntime.resourceID(key); ok {
+		return id, nil
+	}
+	return nil, ErrIllegalOffset
+}
+
+// asArray returns the given value as *Array if it is either Array or *Array.
+func asArray(value any) (*Array, bool) {
+	switch v := value.(type) {
+	case *Array:
+		return v, v != nil
+	case Array:
+		return &v, true
+	default:
+		return nil, false
+	}
+}
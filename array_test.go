@@ -0,0 +1,199 @@
+package gophplib
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+)
+
+func ExampleArray() {
+	arr := NewArray()
+	arr.Append("a")
+	arr.Set("5", "b")
+	arr.Append("c")
+	arr.Set("05", "d")
+	arr.Set(true, "e")
+	arr.Set(nil, "f")
+
+	arr.Range(func(key, value any) bool {
+		fmt.Printf("%#v => %#v\n", key, value)
+		return true
+	})
+	fmt.Println(Implode(",", arr))
+	// Output:
+	// 0 => "a"
+	// 5 => "b"
+	// 6 => "c"
+	// "05" => "d"
+	// 1 => "e"
+	// "" => "f"
+	// a,b,c,d,e,f <nil>
+}
+
+func ExampleParseStrArray() {
+	arr := ParseStrArray("a[]=1&a[]=2&b[c]=3")
+	a, _ := arr.Get("a")
+	fmt.Println(a.(*Array).Values())
+	b, _ := arr.Get("b")
+	fmt.Println(b.(*Array).Get("c"))
+	// Output:
+	// [1 2]
+	// 3 true
+}
+
+// TestArrayKey tests key normalization of Array.
+//
+// References:
+//   - https://github.com/php/php-src/blob/php-5.6.40/Zend/tests/offset_array.phpt
+//   - https://www.php.net/manual/en/language.types.array.php
+func TestArrayKey(t *testing.T) {
+	testCases := []struct {
+		key      any
+		expected any
+	}{
+		{0, 0},
+		{int8(-1), -1},
+		{int64(math.MaxInt64), math.MaxInt},
+		{"0", 0},
+		{"123", 123},
+		{"-123", -123},
+		{"0123", "0123"},
+		{"+1", "+1"},
+		{"-0", "-0"},
+		{"1.5", "1.5"},
+		{" 1", " 1"},
+		{"9223372036854775808", "9223372036854775808"},
+		{"abc", "abc"},
+		{1.5, 1},
+		{-1.5, -1},
+		{float32(2.5), 2},
+		{true, 1},
+		{false, 0},
+		{nil, ""},
+	}
+
+	for _, tc := range testCases {
+		arr := NewArray()
+		if err := arr.Set(tc.key, "value"); err != nil {
+			t.Errorf("Set(%#v) returned error: %v", tc.key, err)
+			continue
+		}
+		if keys := arr.Keys(); len(keys) != 1 || keys[0] != tc.expected {
+			t.Errorf("Set(%#v) stored key %#v; want %#v", tc.key, keys, tc.expected)
+		}
+		if _, ok := arr.Get(tc.key); !ok {
+			t.Errorf("Get(%#v) returned false", tc.key)
+		}
+		if _, ok := arr.Get(tc.expected); !ok {
+			t.Errorf("Get(%#v) returned false", tc.expected)
+		}
+	}
+
+	for _, key := range []any{[]int{}, NewArray(), Point{}, uint(1)} {
+		if err := NewArray().Set(key, "value"); !errors.Is(err, ErrIllegalOffset) {
+			t.Errorf("Set(%#v) returned error %v; want %v", key, err, ErrIllegalOffset)
+		}
+	}
+}
+
+// TestArrayAppend tests the next integer key of Array.
+//
+// References:
+//   - https://github.com/php/php-src/blob/php-5.6.40/Zend/tests/bug47836.phpt
+func TestArrayAppend(t *testing.T) {
+	var arr Array
+	arr.Append("a")
+	arr.Set(-5, "b")
+	arr.Append("c")
+	arr.Set(10, "d")
+	arr.Delete(10)
+	arr.Append("e")
+	if expected := omap(0, "a", -5, "b", 1, "c", 11, "e"); !Identical(&arr, expected) {
+		t.Errorf("got %s; want %s", dumpOrderedMap(arr.ToOrderedMap()), dumpOrderedMap(expected))
+	}
+
+	full := NewArray()
+	full.Set(math.MaxInt, "a")
+	if err := full.Append("b"); !errors.Is(err, ErrNextElementOccupied) {
+		t.Errorf("Append returned error %v; want %v", err, ErrNextElementOccupied)
+	}
+	if full.Len() != 1 {
+		t.Errorf("Len() = %d; want 1", full.Len())
+	}
+}
+
+func TestArrayDelete(t *testing.T) {
+	arr := NewArray()
+	arr.Set("a", 1)
+	arr.Set(1, 2)
+	if !arr.Delete("1") {
+		t.Error(`Delete("1") returned false`)
+	}
+	if arr.Delete("b") {
+		t.Error(`Delete("b") returned true`)
+	}
+	if arr.Delete([]int{}) {
+		t.Error(`Delete([]int{}) returned true`)
+	}
+	if arr.Len() != 1 {
+		t.Errorf("Len() = %d; want 1", arr.Len())
+	}
+	if (&Array{}).Delete("a") {
+		t.Error(`Delete("a") on empty array returned true`)
+	}
+}
+
+func TestArrayCopy(t *testing.T) {
+	arr := ParseStrArray("a[x]=1&a[y]=2&b=3")
+	arr.Set(10, "c")
+	arr.Delete(10)
+
+	copied := arr.Copy()
+	sub, _ := copied.Get("a")
+	sub.(*Array).Set("x", "changed")
+	copied.Set("b", "changed")
+	copied.Append("d")
+
+	if expected := omap("a", omap("x", "1", "y", "2"), "b", "3"); !Identical(arr, expected) {
+		t.Errorf("original array changed: %s", dumpOrderedMap(arr.ToOrderedMap()))
+	}
+	if expected := omap("a", omap("x", "changed", "y", "2"), "b", "changed", 11, "d"); !Identical(copied, expected) {
+		t.Errorf("got %s; want %s", dumpOrderedMap(copied.ToOrderedMap()), dumpOrderedMap(expected))
+	}
+}
+
+func TestNewArrayFrom(t *testing.T) {
+	arr, err := NewArrayFrom(omap("1", "a", "b", []any{"c", map[string]int{"d": 1}}))
+	if err != nil {
+		t.Fatalf("NewArrayFrom returned error: %v", err)
+	}
+	expected := omap(1, "a", "b", omap(0, "c", 1, omap("d", 1)))
+	if !Identical(arr, expected) {
+		t.Errorf("got %s; want %s", dumpOrderedMap(arr.ToOrderedMap()), dumpOrderedMap(expected))
+	}
+	if err := arr.Append("e"); err != nil || !Identical(arr.Keys(), []any{1, "b", 2}) {
+		t.Errorf("Append returned %v and keys %v; want keys [1 b 2]", err, arr.Keys())
+	}
+
+	if _, err := NewArrayFrom(map[Point]int{{}: 1}); !errors.Is(err, ErrIllegalOffset) {
+		t.Errorf("NewArrayFrom returned error %v; want %v", err, ErrIllegalOffset)
+	}
+}
+
+func TestArrayConversions(t *testing.T) {
+	empty, arr := NewArray(), ParseStrArray("a=1")
+
+	if s, err := ConvertToString(arr); s != "Array" || err != nil {
+		t.Errorf("ConvertToString = %q, %v; want \"Array\"", s, err)
+	}
+	if ConvertToBool(empty) || !ConvertToBool(arr) {
+		t.Errorf("ConvertToBool returned wrong result")
+	}
+	if l, _ := ConvertToLong(arr); l != 1 {
+		t.Errorf("ConvertToLong = %d; want 1", l)
+	}
+	if ret, err := Compare(arr, map[string]string{"a": "1"}); ret != 0 || err != nil {
+		t.Errorf("Compare = %d, %v; want 0", ret, err)
+	}
+}
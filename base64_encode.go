@@ -2,15 +2,14 @@ package gophplib
 
 import (
 	"encoding/base64"
-	"fmt"
 	"math"
-	"reflect"
 )
 
-// Base64Encode emulates the functionality of PHP 5.6's base64_encode function.
+// Base64Encode emulates the functionality of PHP's base64_encode function.
 // For more information, see the [official PHP documentation].
 // In PHP 5.6, an error is triggered if the input size is excessively large due to memory limitations.
 // This Go implementation includes similar checks to emulate PHP's memory limitation conditions.
+// PHP 7 and later do not have such limitation on 64-bit platforms, so the check is skipped for them.
 // Additionally, this function converts different types of variables to a string, following PHP's dynamic typing approach.
 // After ensuring the memory constraints are met and converting the input to a string,
 // it uses the EncodeToString function from the encoding/base64 package to perform the Base64 encoding.
@@ -19,14 +18,16 @@ import (
 // see the [encoding/base64's EncodeToString documentation]
 //
 // This function returns error if given argument is not one of following:
-// string, int, int64, float64, bool, nil, and any type which does not implement
-// interface { toString() string }.
+// string, int, int64, float64, bool, nil, and any type which implements
+// Stringable.
 //
 // PHP references:
 //   - base64_encode definition:
 //     https://github.com/php/php-src/blob/php-5.6.40/ext/standard/base64.c#L224-L241
 //   - base64_encode implementation:
 //     https://github.com/php/php-src/blob/4b8f72da5dfb201af4e82dee960261d8657e414f/ext/standard/base64.c#L56-L106
+//   - base64_encode implementation of PHP 7:
+//     https://github.com/php/php-src/blob/php-7.0.0/ext/standard/base64.c
 //
 // Test Cases :
 //   - https://github.com/php/php-src/blob/php-5.6.40/ext/standard/tests/url/base64_encode_basic_001.phpt
@@ -36,11 +37,11 @@ import (
 //
 // [official PHP documentation]: https://www.php.net/manual/en/function.base64-encode
 // [encoding/base64's EncodeToString documentation]: https://pkg.go.dev/encoding/base64#Encoding.EncodeToString
-func Base64Encode(value any) (string, error) {
+func (r *Runtime) Base64Encode(value any) (string, error) {
 	// Convert a value to string
-	characterString, err := zendParseArgAsString(value)
-	if err != nil {
-		return "", fmt.Errorf("unsupported type : %s", reflect.TypeOf(value))
+	var characterString string
+	if err := r.ParseParameters(Function{"base64_encode", []string{"string"}}, []any{value}, "s", &characterString); err != nil {
+		return "", err
 	}
 
 	// Base64 encoding converts 3 bytes into 4 bytes ASCII characters,
@@ -50,9 +51,19 @@ func Base64Encode(value any) (string, error) {
 	// In Go, such checks are generally not required thanks to its robust memory management system.
 	// However, to maintain exact behavioral parity with the PHP implementation,
 	// this function includes memory limit check too.
-	if (len(characterString)+2)/3 > math.MaxInt32/4 {
-		return "", fmt.Errorf("string too long, maximum is 1610612733")
+	if r.Version < PHP70 && (len(characterString)+2)/3 > math.MaxInt32/4 {
+		return "", &ValueError{
+			Function:  "base64_encode",
+			Param:     1,
+			ParamName: "string",
+			Message:   "string too long, maximum is 1610612733",
+		}
 	}
 	encodedString := base64.StdEncoding.EncodeToString([]byte(characterString))
 	return encodedString, nil
 }
+
+// Base64Encode works the same as Runtime.Base64Encode of a Runtime emulating PHP 5.6.
+func Base64Encode(value any) (string, error) {
+	return defaultRuntime.Base64Encode(value)
+}
@@ -26,7 +26,7 @@ func ExampleBase64Encode() {
 	// MTAuNQ== <nil>
 	//  <nil>
 	//  <nil>
-	//  unsupported type : []int
+	//  base64_encode() expects parameter 1 to be string, array given
 }
 
 func TestBase64Encode(t *testing.T) {
@@ -85,7 +85,7 @@ func TestBase64Encode(t *testing.T) {
 		t.Run(testName, func(t *testing.T) {
 			result, err := Base64Encode(tc.any)
 			if err != nil {
-				stringErr := fmt.Errorf("unsupported type : %s", reflect.TypeOf(tc.any))
+				stringErr := fmt.Errorf("base64_encode() expects parameter 1 to be string, %s given", defaultRuntime.zvalTypeName(tc.any))
 				if err.Error() != stringErr.Error() {
 					t.Errorf("%s: string error : %s, got %s", testName, stringErr, err)
 				}
@@ -0,0 +1,152 @@
+package gophplib
+
+import "fmt"
+
+// ErrorLevel is the level of an error emitted by PHP, which has the same value
+// as PHP's E_* constant of the level.
+//
+// Reference:
+//   - https://www.php.net/manual/en/errorfunc.constants.php
+type ErrorLevel int
+
+const (
+	EError            ErrorLevel = 1
+	EWarning          ErrorLevel = 2
+	EParse            ErrorLevel = 4
+	ENotice           ErrorLevel = 8
+	ECoreError        ErrorLevel = 16
+	ECoreWarning      ErrorLevel = 32
+	ECompileError     ErrorLevel = 64
+	ECompileWarning   ErrorLevel = 128
+	EUserError        ErrorLevel = 256
+	EUserWarning      ErrorLevel = 512
+	EUserNotice       ErrorLevel = 1024
+	EStrict           ErrorLevel = 2048
+	ERecoverableError ErrorLevel = 4096
+	EDeprecated       ErrorLevel = 8192
+	EUserDeprecated   ErrorLevel = 16384
+)
+
+// String returns the name of PHP's constant of the level. (ex: "E_WARNING")
+func (l ErrorLevel) String() string {
+	switch l {
+	case EError:
+		return "E_ERROR"
+	case EWarning:
+		return "E_WARNING"
+	case EParse:
+		return "E_PARSE"
+	case ENotice:
+		return "E_NOTICE"
+	case ECoreError:
+		return "E_CORE_ERROR"
+	case ECoreWarning:
+		return "E_CORE_WARNING"
+	case ECompileError:
+		return "E_COMPILE_ERROR"
+	case ECompileWarning:
+		return "E_COMPILE_WARNING"
+	case EUserError:
+		return "E_USER_ERROR"
+	case EUserWarning:
+		return "E_USER_WARNING"
+	case EUserNotice:
+		return "E_USER_NOTICE"
+	case EStrict:
+		return "E_STRICT"
+	case ERecoverableError:
+		return "E_RECOVERABLE_ERROR"
+	case EDeprecated:
+		return "E_DEPRECATED"
+	case EUserDeprecated:
+		return "E_USER_DEPRECATED"
+	default:
+		return fmt.Sprintf("ErrorLevel(%d)", int(l))
+	}
+}
+
+// Diagnostic is a notice, a warning or a deprecation emitted by PHP while a
+// function keeps running, such as E_NOTICE "Array to string conversion".
+type Diagnostic struct {
+	// Level is the level of the diagnostic.
+	Level ErrorLevel
+
+	// Message is the message of the diagnostic, exactly the same as PHP's.
+	// Messages of functions are prefixed with the function name, like
+	// "implode(): Invalid arguments passed".
+	Message string
+}
+
+// String formats the diagnostic like PHP's display_errors does, without the
+// file name and the line number. (ex: "Notice: Array to string conversion")
+//
+// Reference:
+//   - https://github.com/php/php-src/blob/php-8.3.0/main/main.c
+func (d Diagnostic) String() string {
+	var label string
+	switch d.Level {
+	case EError, ECoreError, ECompileError, EUserError:
+		label = "Fatal error"
+	case ERecoverableError:
+		label = "Recoverable fatal error"
+	case EWarning, ECoreWarning, ECompileWarning, EUserWarning:
+		label = "Warning"
+	case EParse:
+		label = "Parse error"
+	case ENotice, EUserNotice:
+		label = "Notice"
+	case EStrict:
+		label = "Strict Standards"
+	case EDeprecated, EUserDeprecated:
+		label = "Deprecated"
+	default:
+		label = "Unknown error"
+	}
+	return label + ": " + d.Message
+}
+
+// DiagnosticHandler receives diagnostics emitted by the functions of a
+// Runtime.
+type DiagnosticHandler func(Diagnostic)
+
+// WithDiagnosticHandler returns a copy of r which passes diagnostics to
+// handler. It can be used to receive the diagnostics of a single call:
+//
+//	var diags []Diagnostic
+//	s, err := r.WithDiagnosticHandler(func(d Diagnostic) {
+//		diags = append(diags, d)
+//	}).Implode(",", values)
+func (r *Runtime) WithDiagnosticHandler(handler DiagnosticHandler) *Runtime {
+	copied := *r
+	copied.DiagnosticHandler = handler
+	return &copied
+}
+
+// emit passes a diagnostic to the handler of r, if any. It is the same as
+// PHP's zend_error function.
+func (r *Runtime) emit(level ErrorLevel, format string, args ...any) {
+	if r.DiagnosticHandler == nil {
+		return
+	}
+	r.DiagnosticHandler(Diagnostic{Level: level, Message: fmt.Sprintf(format, args...)})
+}
+
+// warning emits E_WARNING with the message of the given error, and returns
+// the error. It is used for the failures which PHP reports with a warning
+// before returning NULL.
+func (r *Runtime) warning(err error) error {
+	r.emit(EWarning, "%s", err.Error())
+	return err
+}
+
+// noticeOrWarning returns E_NOTICE before PHP 8, and E_WARNING since PHP 8.
+// Many notices were promoted to warnings in PHP 8.
+//
+// Reference:
+//   - https://wiki.php.net/rfc/engine_warnings
+func (r *Runtime) noticeOrWarning() ErrorLevel {
+	if r.Version >= PHP80 {
+		return EWarning
+	}
+	return ENotice
+}
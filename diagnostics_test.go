@@ -0,0 +1,122 @@
+package gophplib
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func ExampleRuntime_WithDiagnosticHandler() {
+	r := NewRuntime(PHP74)
+	handler := func(d Diagnostic) {
+		fmt.Println(d)
+	}
+	fmt.Println(r.WithDiagnosticHandler(handler).Implode([]any{"a", []int{1}}, ", "))
+	fmt.Println(r.Implode([]any{"a", []int{1}}, ", "))
+	// Output:
+	// Deprecated: implode(): Passing the separator after the array is deprecated
+	// Notice: Array to string conversion
+	// a, Array <nil>
+	// a, Array <nil>
+}
+
+func TestDiagnostics(t *testing.T) {
+	testCases := []struct {
+		name     string
+		version  Version
+		call     func(r *Runtime)
+		expected []Diagnostic
+	}{
+		{"ConvertToString array", PHP56, func(r *Runtime) { r.ConvertToString([]int{}) },
+			[]Diagnostic{{ENotice, "Array to string conversion"}}},
+		{"ConvertToString array", PHP80, func(r *Runtime) { r.ConvertToString(NewArray()) },
+			[]Diagnostic{{EWarning, "Array to string conversion"}}},
+		{"ConvertToString string", PHP80, func(r *Runtime) { r.ConvertToString("abc") },
+			nil},
+		{"ConvertToLong object", PHP56, func(r *Runtime) { r.ConvertToLong(Point{}) },
+			[]Diagnostic{{ENotice, "Object of class Point could not be converted to int"}}},
+		{"Implode without array", PHP56, func(r *Runtime) { r.Implode("glue") },
+			[]Diagnostic{{EWarning, "implode(): Argument must be an array"}}},
+		{"Implode without array", PHP80, func(r *Runtime) { r.Implode("glue") },
+			nil},
+		{"Implode invalid arguments", PHP74, func(r *Runtime) { r.Implode("glue", 1) },
+			[]Diagnostic{{EWarning, "implode(): Invalid arguments passed"}}},
+		{"Implode legacy order", PHP56, func(r *Runtime) { r.Implode([]string{"a"}, ",") },
+			nil},
+		{"Implode legacy order", PHP74, func(r *Runtime) { r.Implode([]string{"a"}, ",") },
+			[]Diagnostic{{EDeprecated, "implode(): Passing the separator after the array is deprecated"}}},
+		{"Implode nested array", PHP56, func(r *Runtime) { r.Implode(",", []any{"a", []int{1}}) },
+			[]Diagnostic{{ENotice, "Array to string conversion"}}},
+		{"Strlen array", PHP56, func(r *Runtime) { r.Strlen([]int{}) },
+			[]Diagnostic{{EWarning, "strlen() expects parameter 1 to be string, array given"}}},
+		{"Strlen array", PHP80, func(r *Runtime) { r.Strlen([]int{}) },
+			nil},
+		{"Strlen null", PHP80, func(r *Runtime) { r.Strlen(nil) },
+			nil},
+		{"Strlen null", PHP81, func(r *Runtime) { r.Strlen(nil) },
+			[]Diagnostic{{EDeprecated, "strlen(): Passing null to parameter #1 ($string) of type string is deprecated"}}},
+		{"parameter count", PHP56, func(r *Runtime) {
+			r.ParseParameters(Function{Name: "f"}, nil, "s", new(string))
+		}, []Diagnostic{{EWarning, "f() expects exactly 1 parameter, 0 given"}}},
+		{"leading numeric", PHP56, func(r *Runtime) {
+			r.ParseParameters(Function{Name: "f"}, []any{"12abc"}, "l", new(int64))
+		}, []Diagnostic{{ENotice, "A non well formed numeric value encountered"}}},
+		{"leading numeric", PHP80, func(r *Runtime) {
+			r.ParseParameters(Function{Name: "f"}, []any{"12abc"}, "d", new(float64))
+		}, []Diagnostic{{EWarning, "A non-numeric value encountered"}}},
+		{"fractional float", PHP80, func(r *Runtime) {
+			r.ParseParameters(Function{Name: "f"}, []any{1.5}, "l", new(int64))
+		}, nil},
+		{"fractional float", PHP81, func(r *Runtime) {
+			r.ParseParameters(Function{Name: "f"}, []any{1.5}, "l", new(int64))
+		}, []Diagnostic{{EDeprecated, "Implicit conversion from float 1.5 to int loses precision"}}},
+		{"fractional float string", PHP81, func(r *Runtime) {
+			r.ParseParameters(Function{Name: "f"}, []any{"1.5"}, "l", new(int64))
+		}, []Diagnostic{{EDeprecated, `Implicit conversion from float-string "1.5" to int loses precision`}}},
+		{"arithmetic leading numeric", PHP56, func(r *Runtime) { r.Add(1, "1abc") },
+			nil},
+		{"arithmetic leading numeric", PHP74, func(r *Runtime) { r.Add(1, "1abc") },
+			[]Diagnostic{{ENotice, "A non well formed numeric value encountered"}}},
+		{"arithmetic leading numeric", PHP80, func(r *Runtime) { r.Add(1, "1abc") },
+			[]Diagnostic{{EWarning, "A non-numeric value encountered"}}},
+		{"arithmetic non-numeric", PHP70, func(r *Runtime) { r.Mul("abc", 2) },
+			nil},
+		{"arithmetic non-numeric", PHP71, func(r *Runtime) { r.Mul("abc", 2) },
+			[]Diagnostic{{EWarning, "A non-numeric value encountered"}}},
+	}
+
+	for _, tc := range testCases {
+		var diags []Diagnostic
+		r := NewRuntime(tc.version).WithDiagnosticHandler(func(d Diagnostic) {
+			diags = append(diags, d)
+		})
+		tc.call(r)
+		if !reflect.DeepEqual(diags, tc.expected) {
+			t.Errorf("%s on %d emitted %v; want %v", tc.name, tc.version, diags, tc.expected)
+		}
+	}
+}
+
+func TestDiagnosticString(t *testing.T) {
+	testCases := []struct {
+		diag     Diagnostic
+		expected string
+	}{
+		{Diagnostic{ENotice, "Array to string conversion"}, "Notice: Array to string conversion"},
+		{Diagnostic{EWarning, "A non-numeric value encountered"}, "Warning: A non-numeric value encountered"},
+		{Diagnostic{EDeprecated, "f(): deprecated"}, "Deprecated: f(): deprecated"},
+		{Diagnostic{ERecoverableError, "error"}, "Recoverable fatal error: error"},
+	}
+	for _, tc := range testCases {
+		if s := tc.diag.String(); s != tc.expected {
+			t.Errorf("String() = %q; want %q", s, tc.expected)
+		}
+	}
+
+	if s := EUserDeprecated.String(); s != "E_USER_DEPRECATED" {
+		t.Errorf("String() = %q; want E_USER_DEPRECATED", s)
+	}
+	if s := ErrorLevel(3).String(); s != "ErrorLevel(3)" {
+		t.Errorf("String() = %q; want ErrorLevel(3)", s)
+	}
+}
@@ -0,0 +1,172 @@
+package gophplib
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Sentinel errors which mirror the hierarchy of PHP 8's Error classes. Errors
+// returned by this package can be matched against them with errors.Is. For
+// example, errors.Is(err, ErrTypeError) reports whether PHP 8 throws
+// TypeError or one of its subclasses, such as ArgumentCountError, in the same
+// situation.
+//
+// Before PHP 8, PHP emits a warning and returns NULL, or raises a fatal error
+// in most of these situations. Errors returned by a Runtime emulating such
+// versions have the message of the warning or the fatal error, but they are
+// matched against the same sentinel errors as PHP 8.
+//
+// Reference:
+//   - https://www.php.net/manual/en/reserved.exceptions.php
+var (
+	// ErrError matches PHP's Error and all of its subclasses.
+	ErrError = errors.New("Error")
+	// ErrTypeError matches PHP's TypeError and ArgumentCountError.
+	ErrTypeError = errors.New("TypeError")
+	// ErrArgumentCountError matches PHP's ArgumentCountError.
+	ErrArgumentCountError = errors.New("ArgumentCountError")
+	// ErrValueError matches PHP's ValueError.
+	ErrValueError = errors.New("ValueError")
+	// ErrArithmeticError matches PHP's ArithmeticError and
+	// DivisionByZeroError.
+	ErrArithmeticError = errors.New("ArithmeticError")
+	// ErrDivisionByZeroError matches PHP's DivisionByZeroError.
+	ErrDivisionByZeroError = errors.New("DivisionByZeroError")
+
+	// ErrUnsupportedType matches errors returned for Go values which can not
+	// be represented in PHP, such as functions and channels.
+	ErrUnsupportedType = errors.New("unsupported type")
+)
+
+// Error represents PHP's Error, which is thrown for errors that are neither
+// TypeError, ValueError nor ArithmeticError. (ex: "Object of class Dog could
+// not be converted to string")
+type Error struct {
+	// Message is the message of the error, exactly the same as PHP's.
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Is reports whether target is ErrError.
+func (e *Error) Is(target error) bool {
+	return target == ErrError
+}
+
+// TypeError represents PHP's TypeError, which is thrown when a value is not of
+// the expected type.
+type TypeError struct {
+	// Function is the name of the function which received the value, if the
+	// value is an argument of a function. (ex: "strlen")
+	Function string
+	// Param is the 1-based index of the parameter, if the value is an
+	// argument of a function. Otherwise it is 0.
+	Param int
+	// ParamName is the name of the parameter without the leading '$', if
+	// known.
+	ParamName string
+	// Expected is the PHP 8 name of the expected type. (ex: "string", "?array")
+	Expected string
+	// Given is the PHP 8 name of the type of the given value. (ex: "array")
+	Given string
+
+	// Message is the message of the error, exactly the same as PHP's.
+	Message string
+}
+
+func (e *TypeError) Error() string {
+	return e.Message
+}
+
+// Is reports whether target is either ErrTypeError or ErrError.
+func (e *TypeError) Is(target error) bool {
+	return target == ErrTypeError || target == ErrError
+}
+
+// ArgumentCountError represents PHP's ArgumentCountError, which is thrown when
+// a function is called with too few or too many arguments.
+type ArgumentCountError struct {
+	// Function is the name of the function.
+	Function string
+	// Min and Max are the minimum and the maximum number of arguments of the
+	// function.
+	Min, Max int
+	// Given is the number of the given arguments.
+	Given int
+
+	// Message is the message of the error, exactly the same as PHP's.
+	Message string
+}
+
+func (e *ArgumentCountError) Error() string {
+	return e.Message
+}
+
+// Is reports whether target is one of ErrArgumentCountError, ErrTypeError and
+// ErrError.
+func (e *ArgumentCountError) Is(target error) bool {
+	return target == ErrArgumentCountError || target == ErrTypeError || target == ErrError
+}
+
+// ValueError represents PHP's ValueError, which is thrown when a value is of
+// the correct type, but the value itself is invalid.
+type ValueError struct {
+	// Function is the name of the function which received the value, if the
+	// value is an argument of a function.
+	Function string
+	// Param is the 1-based index of the parameter, if the value is an
+	// argument of a function. Otherwise it is 0.
+	Param int
+	// ParamName is the name of the parameter without the leading '$', if
+	// known.
+	ParamName string
+
+	// Message is the message of the error, exactly the same as PHP's.
+	Message string
+}
+
+func (e *ValueError) Error() string {
+	return e.Message
+}
+
+// Is reports whether target is either ErrValueError or ErrError.
+func (e *ValueError) Is(target error) bool {
+	return target == ErrValueError || target == ErrError
+}
+
+// DivisionByZeroError represents PHP's DivisionByZeroError, which is thrown
+// when dividing a number by zero. PHP's ArithmeticError has no other
+// subclasses, and is not thrown by the functions of this package.
+type DivisionByZeroError struct {
+	// Message is the message of the error, exactly the same as PHP's. (ex:
+	// "Division by zero")
+	Message string
+}
+
+func (e *DivisionByZeroError) Error() string {
+	return e.Message
+}
+
+// Is reports whether target is one of ErrDivisionByZeroError,
+// ErrArithmeticError and ErrError.
+func (e *DivisionByZeroError) Is(target error) bool {
+	return target == ErrDivisionByZeroError || target == ErrArithmeticError || target == ErrError
+}
+
+// UnsupportedTypeError is returned for Go values which can not be represented
+// in PHP, such as functions and channels.
+type UnsupportedTypeError struct {
+	// Value is the unsupported value.
+	Value any
+}
+
+func (e *UnsupportedTypeError) Error() string {
+	return fmt.Sprintf("unsupported type : %T", e.Value)
+}
+
+// Is reports whether target is ErrUnsupportedType.
+func (e *UnsupportedTypeError) Is(target error) bool {
+	return target == ErrUnsupportedType
+}
@@ -0,0 +1,106 @@
+package gophplib
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func ExampleTypeError() {
+	for _, version := range []Version{PHP56, PHP80} {
+		_, err := NewRuntime(version).Strlen([]int{1})
+
+		var typeErr *TypeError
+		if errors.As(err, &typeErr) {
+			fmt.Println(typeErr.Function, typeErr.Param, typeErr.Expected, typeErr.Given)
+			fmt.Println(typeErr)
+		}
+	}
+	// Output:
+	// strlen 1 string array
+	// strlen() expects parameter 1 to be string, array given
+	// strlen 1 string array
+	// strlen(): Argument #1 ($string) must be of type string, array given
+}
+
+func TestErrors(t *testing.T) {
+	testCases := []struct {
+		name       string
+		version    Version
+		call       func(r *Runtime) error
+		matches    []error
+		notMatches []error
+	}{
+		{"Strlen array", PHP56, func(r *Runtime) error { _, err := r.Strlen(NewArray()); return err },
+			[]error{ErrTypeError, ErrError}, []error{ErrArgumentCountError, ErrValueError}},
+		{"Strlen array", PHP80, func(r *Runtime) error { _, err := r.Strlen(NewArray()); return err },
+			[]error{ErrTypeError, ErrError}, []error{ErrArgumentCountError, ErrValueError}},
+		{"parameter count", PHP56, func(r *Runtime) error {
+			return r.ParseParameters(Function{Name: "f"}, nil, "s", new(string))
+		}, []error{ErrArgumentCountError, ErrTypeError, ErrError}, []error{ErrValueError}},
+		{"parameter count", PHP80, func(r *Runtime) error {
+			return r.ParseParameters(Function{Name: "f"}, []any{"a", "b"}, "s", new(string))
+		}, []error{ErrArgumentCountError, ErrTypeError, ErrError}, []error{ErrValueError}},
+		{"null byte in path", PHP80, func(r *Runtime) error {
+			return r.ParseParameters(Function{Name: "f"}, []any{"a\x00b"}, "p", new(string))
+		}, []error{ErrValueError, ErrError}, []error{ErrTypeError}},
+		{"null byte in path", PHP56, func(r *Runtime) error {
+			return r.ParseParameters(Function{Name: "f"}, []any{"a\x00b"}, "p", new(string))
+		}, []error{ErrTypeError, ErrError}, []error{ErrValueError}},
+		{"Implode invalid arguments", PHP56, func(r *Runtime) error { _, err := r.Implode("a", "b"); return err },
+			[]error{ErrTypeError}, []error{ErrArgumentCountError}},
+		{"Implode invalid arguments", PHP80, func(r *Runtime) error { _, err := r.Implode("a", "b"); return err },
+			[]error{ErrTypeError}, []error{ErrArgumentCountError}},
+		{"Implode object", PHP80, func(r *Runtime) error { _, err := r.Implode(",", []any{Dog{}}); return err },
+			[]error{ErrError}, []error{ErrTypeError}},
+		{"Div by zero", PHP80, func(r *Runtime) error { _, err := r.Div(1, 0); return err },
+			[]error{ErrDivisionByZeroError, ErrArithmeticError, ErrError}, []error{ErrTypeError}},
+		{"Mod by zero", PHP70, func(r *Runtime) error { _, err := r.Mod(1, 0); return err },
+			[]error{ErrDivisionByZeroError, ErrArithmeticError, ErrError}, []error{ErrValueError}},
+		{"unsupported operands", PHP80, func(r *Runtime) error { _, err := r.Add(NewArray(), 1); return err },
+			[]error{ErrTypeError, ErrError}, []error{ErrArithmeticError}},
+		{"illegal offset", PHP80, func(r *Runtime) error { return NewArray().Set(NewArray(), 1) },
+			[]error{ErrTypeError, ErrError}, []error{ErrUnsupportedType}},
+		{"unsupported type", PHP56, func(r *Runtime) error { _, err := r.ConvertToString(func() {}); return err },
+			[]error{ErrUnsupportedType}, []error{ErrError}},
+		{"unsupported type", PHP80, func(r *Runtime) error { _, err := r.Add(make(chan int), 1); return err },
+			[]error{ErrUnsupportedType}, []error{ErrError}},
+	}
+
+	for _, tc := range testCases {
+		err := tc.call(NewRuntime(tc.version))
+		if err == nil {
+			t.Errorf("%s on %d returned no error", tc.name, tc.version)
+			continue
+		}
+		for _, target := range tc.matches {
+			if !errors.Is(err, target) {
+				t.Errorf("%s on %d: errors.Is(%q, %v) = false; want true", tc.name, tc.version, err, target)
+			}
+		}
+		for _, target := range tc.notMatches {
+			if errors.Is(err, target) {
+				t.Errorf("%s on %d: errors.Is(%q, %v) = true; want false", tc.name, tc.version, err, target)
+			}
+		}
+	}
+}
+
+func TestArgumentCountError(t *testing.T) {
+	err := NewRuntime(PHP80).ParseParameters(Function{Name: "f"}, []any{1, 2, 3}, "l|l", new(int64), new(int64))
+
+	var countErr *ArgumentCountError
+	if !errors.As(err, &countErr) {
+		t.Fatalf("errors.As(%v) = false; want true", err)
+	}
+	expected := ArgumentCountError{
+		Function: "f",
+		Min:      1,
+		Max:      2,
+		Given:    3,
+		Message:  "f() expects at most 2 arguments, 3 given",
+	}
+	if *countErr != expected {
+		t.Errorf("got %+v; want %+v", *countErr, expected)
+	}
+}
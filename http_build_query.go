@@ -0,0 +1,185 @@
+package gophplib
+
+import (
+	"strconv"
+	"strings"
+)
+
+// QueryEncoding is the encoding of the keys and the values of a query string
+// built by HttpBuildQuery, which is the enc_type parameter of PHP's
+// http_build_query function.
+type QueryEncoding int
+
+const (
+	// QueryRFC1738 encodes like Urlencode, so spaces are encoded as '+'. It
+	// is PHP_QUERY_RFC1738.
+	QueryRFC1738 QueryEncoding = 1
+	// QueryRFC3986 encodes like Rawurlencode, so spaces are encoded as
+	// "%20". It is PHP_QUERY_RFC3986.
+	QueryRFC3986 QueryEncoding = 2
+)
+
+// HttpBuildQuery is a ported function that works exactly the same as PHP's
+// http_build_query function. For more information, see the [official PHP
+// documentation].
+//
+// It builds a URL-encoded query string from the given array or object, which
+// is the inverse of ParseStr:
+//   - Arrays are either Array, orderedmap.OrderedMap, slices, arrays or maps.
+//     Go maps have no order, so use the others if the order of the variables
+//     matters, such as when the result is signed.
+//   - Objects are structs or pointers to structs, and only their exported
+//     fields are used, like PHP uses only the public properties.
+//   - Nested arrays and objects are encoded as "a%5Bb%5D=c", which is
+//     "a[b]=c" encoded. Arrays and objects which contain themselves are
+//     skipped when they are encountered again.
+//   - nil and resources are skipped, bools are encoded as "1" and "0", and
+//     floats are converted to strings using the "precision" INI setting
+//     without being encoded, so 1e25 becomes "1.0E+25".
+//
+// numericPrefix is prepended to the integer keys of the top-level array, and
+// argSeparator separates the variables. If argSeparator is empty,
+// ArgSeparatorOutput of the INI settings of the Runtime is used. Keys and
+// values are encoded with Rawurlencode if encType is QueryRFC3986, and with
+// Urlencode otherwise.
+//
+// If data is neither an array nor an object, it returns *TypeError with the
+// same message as the warning emitted by PHP, or the TypeError thrown by PHP
+// 8. If data contains values of unsupported types, it returns
+// *UnsupportedTypeError.
+//
+// References:
+//   - https://github.com/php/php-src/blob/php-5.6.40/ext/standard/http.c
+//   - https://github.com/php/php-src/blob/php-8.3.0/ext/standard/http.c
+//
+// [official PHP documentation]: https://www.php.net/manual/en/function.http-build-query.php
+func (r *Runtime) HttpBuildQuery(data any, numericPrefix, argSeparator string, encType QueryEncoding) (string, error) {
+	if t := zvalTypeOf(data); t != typeArray && t != typeObject {
+		err := &TypeError{Function: "http_build_query", Param: 1, ParamName: "data", Expected: "array", Given: zendZvalTypeName(data)}
+		if r.Version >= PHP80 {
+			err.Message = "http_build_query(): Argument #1 ($data) must be of type array, " + err.Given + " given"
+			return "", err
+		}
+		err.Message = "http_build_query(): Parameter 1 expected to be Array or Object.  Incorrect value given"
+		return "", r.warning(err)
+	}
+
+	if argSeparator == "" {
+		argSeparator = r.ini().ArgSeparatorOutput
+	}
+
+	b := queryBuilder{r: r, argSeparator: argSeparator, raw: encType == QueryRFC3986, visiting: make(map[any]bool)}
+	if err := b.encodeHash(data, numericPrefix, ""); err != nil {
+		return "", err
+	}
+	return b.buf.String(), nil
+}
+
+// HttpBuildQuery works the same as Runtime.HttpBuildQuery of a Runtime emulating PHP 5.6.
+func HttpBuildQuery(data any, numericPrefix, argSeparator string, encType QueryEncoding) (string, error) {
+	return defaultRuntime.HttpBuildQuery(data, numericPrefix, argSeparator, encType)
+}
+
+// queryBuilder builds a query string for HttpBuildQuery.
+type queryBuilder struct {
+	r            *Runtime
+	buf          strings.Builder
+	argSeparator string
+	raw          bool
+	// visiting is the set of arrays and objects being encoded, to skip
+	// recursive ones.
+	visiting map[any]bool
+}
+
+// encodeHash is a ported function that works exactly the same as PHP's
+// php_url_encode_hash_ex function. keyPrefix is either empty for the
+// top-level array, or the encoded name of the array followed by "%5B".
+func (b *queryBuilder) encodeHash(data any, numericPrefix, keyPrefix string) error {
+	if key, ok := recursionKey(data); ok {
+		if b.visiting[key] {
+			// Prevent recursion
+			return nil
+		}
+		b.visiting[key] = true
+		defer delete(b.visiting, key)
+	}
+
+	var entries []arrayEntry
+	if zvalTypeOf(data) == typeObject {
+		for _, prop := range objectProperties(data) {
+			// property not visible in this scope
+			if !prop.public {
+				continue
+			}
+			entries = append(entries, arrayEntry{prop.name, prop.value})
+		}
+	} else {
+		entries = aggregateEntries(data)
+	}
+
+	for _, e := range entries {
+		// Build the key, which is "ekey" in the original PHP codes
+		var key string
+		switch k := e.key.(type) {
+		case string:
+			key = urlencode(k, b.raw)
+		case int:
+			key = numericPrefix + strconv.Itoa(k)
+		default:
+			return &UnsupportedTypeError{e.key}
+		}
+
+		switch zvalTypeOf(e.value) {
+		case typeArray, typeObject:
+			newPrefix := key + "%5B"
+			if keyPrefix != "" {
+				newPrefix = keyPrefix + key + "%5D%5B"
+			}
+			if err := b.encodeHash(e.value, "", newPrefix); err != nil {
+				return err
+			}
+			continue
+		case typeNull, typeResource:
+			// Skip these types
+			continue
+		}
+
+		value, err := b.encodeScalar(e.value)
+		if err != nil {
+			return err
+		}
+		if b.buf.Len() > 0 {
+			b.buf.WriteString(b.argSeparator)
+		}
+		if keyPrefix != "" {
+			b.buf.WriteString(keyPrefix)
+			b.buf.WriteString(key)
+			b.buf.WriteString("%5D")
+		} else {
+			b.buf.WriteString(key)
+		}
+		b.buf.WriteByte('=')
+		b.buf.WriteString(value)
+	}
+	return nil
+}
+
+// encodeScalar encodes the given scalar value like PHP's
+// php_url_encode_scalar function.
+func (b *queryBuilder) encodeScalar(value any) (string, error) {
+	switch zvalTypeOf(value) {
+	case typeBool:
+		if value.(bool) {
+			return "1", nil
+		}
+		return "0", nil
+	case typeLong:
+		return strconv.FormatInt(longOf(value), 10), nil
+	case typeDouble:
+		return b.r.floatToString(doubleOf(value)), nil
+	case typeString:
+		return urlencode(value.(string), b.raw), nil
+	default:
+		return "", &UnsupportedTypeError{value}
+	}
+}
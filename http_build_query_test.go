@@ -0,0 +1,170 @@
+package gophplib
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/elliotchance/orderedmap/v2"
+)
+
+func ExampleHttpBuildQuery() {
+	data := orderedmap.NewOrderedMap[any, any]()
+	data.Set("name", "John Doe")
+	data.Set("tags", []any{"a&b", nil, true})
+	data.Set(0, 1.5)
+	fmt.Println(HttpBuildQuery(data, "n_", "", QueryRFC1738))
+	fmt.Println(HttpBuildQuery(data, "", "&amp;", QueryRFC3986))
+	// Output:
+	// name=John+Doe&tags%5B0%5D=a%26b&tags%5B2%5D=1&n_0=1.5 <nil>
+	// name=John%20Doe&amp;tags%5B0%5D=a%26b&amp;tags%5B2%5D=1&amp;0=1.5 <nil>
+}
+
+type payment struct {
+	ID     string
+	Amount int
+	Buyer  *buyer
+	secret string
+}
+
+type buyer struct {
+	Name  string
+	Email string
+}
+
+// Test cases for HttpBuildQuery. These tests were created using the following
+// test cases in PHP as inspiration.
+//
+// Reference:
+//   - https://www.php.net/manual/en/function.http-build-query.php
+func TestHttpBuildQuery(t *testing.T) {
+	testCases := []struct {
+		data          any
+		numericPrefix string
+		argSeparator  string
+		encType       QueryEncoding
+		expected      string
+	}{
+		{[]int{}, "", "", 0, ""},
+		{[]any{"foo", "bar"}, "", "", 0, "0=foo&1=bar"},
+		{[]any{"foo", []any{"bar"}}, "p_", "", 0, "p_0=foo&p_1%5B0%5D=bar"},
+		{arrayOf("a", nil, "b", false, "c", true, "d", -3, "e", 0.1+0.2, "f", 1e25), "", "", 0, "b=0&c=1&d=-3&e=0.3&f=1.0E+25"},
+		{arrayOf("a b", "c d~", "é", "*"), "", "", QueryRFC1738, "a+b=c+d%7E&%C3%A9=%2A"},
+		{arrayOf("a b", "c d~", "é", "*"), "", "", QueryRFC3986, "a%20b=c%20d~&%C3%A9=%2A"},
+		{arrayOf("a", arrayOf("b", arrayOf("c", "d", 5, "e"))), "", ";", 0, "a%5Bb%5D%5Bc%5D=d;a%5Bb%5D%5B5%5D=e"},
+		{arrayOf("a", arrayOf(), "b", "c"), "", "", 0, "b=c"},
+		{arrayOf("a", getFile(), "b", "c"), "", "", 0, "b=c"},
+		{map[string][]int{"a": {1}}, "", "", 0, "a%5B0%5D=1"},
+		{payment{"p-1", 1000, &buyer{"Kim", "kim@example.com"}, "s"}, "", "", 0, "ID=p-1&Amount=1000&Buyer%5BName%5D=Kim&Buyer%5BEmail%5D=kim%40example.com"},
+		{[]any{&Dog{"choco", 5}, Point{1, 2}}, "x", "", 0, "x1%5BX%5D=1&x1%5BY%5D=2"},
+	}
+	for _, tc := range testCases {
+		result, err := HttpBuildQuery(tc.data, tc.numericPrefix, tc.argSeparator, tc.encType)
+		if err != nil || result != tc.expected {
+			t.Errorf("HttpBuildQuery(%v, %q, %q, %d) = (%q, %v); want %q", tc.data, tc.numericPrefix, tc.argSeparator, tc.encType, result, err, tc.expected)
+		}
+	}
+}
+
+func TestHttpBuildQueryRuntime(t *testing.T) {
+	r := NewRuntime(PHP80)
+	r.INI.Precision = 17
+	r.INI.ArgSeparatorOutput = "&amp;"
+	if result, _ := r.HttpBuildQuery([]any{0.1, "a"}, "", "", 0); result != "0=0.10000000000000001&amp;1=a" {
+		t.Errorf("got %q", result)
+	}
+
+	arr := arrayOf("a", "1")
+	arr.Set("self", arr)
+	arr.Set("b", "2")
+	if result, _ := HttpBuildQuery(arr, "", "", 0); result != "a=1&b=2" {
+		t.Errorf("got %q for recursive array", result)
+	}
+
+	m := map[string]any{"a": "1"}
+	m["self"] = m
+	if result, _ := HttpBuildQuery(m, "", "", 0); result != "a=1" {
+		t.Errorf("got %q for recursive map", result)
+	}
+	s := []any{"1", nil}
+	s[1] = s
+	if result, _ := HttpBuildQuery(s, "", "", 0); result != "0=1" {
+		t.Errorf("got %q for recursive slice", result)
+	}
+}
+
+func TestHttpBuildQueryError(t *testing.T) {
+	testCases := []struct {
+		version Version
+		data    any
+		err     string
+		target  error
+	}{
+		{PHP56, "a=b", "http_build_query(): Parameter 1 expected to be Array or Object.  Incorrect value given", ErrTypeError},
+		{PHP80, "a=b", "http_build_query(): Argument #1 ($data) must be of type array, string given", ErrTypeError},
+		{PHP80, nil, "http_build_query(): Argument #1 ($data) must be of type array, null given", ErrTypeError},
+		{PHP80, []any{func() {}}, "unsupported type : func()", ErrUnsupportedType},
+	}
+	for _, tc := range testCases {
+		var diags []Diagnostic
+		r := NewRuntime(tc.version).WithDiagnosticHandler(func(d Diagnostic) { diags = append(diags, d) })
+		result, err := r.HttpBuildQuery(tc.data, "", "", 0)
+		if err == nil || err.Error() != tc.err || !errors.Is(err, tc.target) || result != "" {
+			t.Errorf("HttpBuildQuery(%v) on %d = (%q, %v); want error %q", tc.data, tc.version, result, err, tc.err)
+		}
+		if tc.version < PHP80 && tc.target == ErrTypeError && len(diags) != 1 {
+			t.Errorf("HttpBuildQuery(%v) on %d emitted %v; want a warning", tc.data, tc.version, diags)
+		}
+	}
+}
+
+// TestHttpBuildQueryRoundTrip checks that ParseStrArray restores the random
+// arrays encoded by HttpBuildQuery. Keys do not contain the characters which
+// ParseStr mangles, such as ' ', '.', '[' and NUL, and arrays are never empty,
+// since empty arrays are not encoded at all.
+func TestHttpBuildQueryRoundTrip(t *testing.T) {
+	const keyChars = "abcXYZ019-_~*'!@#$%^&=+/?;:,\"<>\x7f\x80\xb0\xff한"
+	rnd := rand.New(rand.NewSource(1))
+
+	randomString := func(chars string, n int) string {
+		b := make([]byte, n)
+		for i := range b {
+			b[i] = chars[rnd.Intn(len(chars))]
+		}
+		return string(b)
+	}
+
+	var randomArray func(depth int) *Array
+	randomArray = func(depth int) *Array {
+		arr := NewArray()
+		for n := 1 + rnd.Intn(4); arr.Len() < n; {
+			var key any
+			if rnd.Intn(3) == 0 {
+				key = rnd.Intn(200) - 100
+			} else {
+				key = randomString(keyChars, 1+rnd.Intn(6))
+			}
+			if depth > 0 && rnd.Intn(3) == 0 {
+				arr.Set(key, randomArray(depth-1))
+			} else {
+				arr.Set(key, randomString(keyChars+" .[]\t\x00", rnd.Intn(8)))
+			}
+		}
+		return arr
+	}
+
+	for i := 0; i < 1000; i++ {
+		data := randomArray(3)
+		for _, encType := range []QueryEncoding{QueryRFC1738, QueryRFC3986} {
+			query, err := HttpBuildQuery(data, "", "", encType)
+			if err != nil {
+				t.Fatalf("HttpBuildQuery(%s) returned error %v", dumpOrderedMap(data.ToOrderedMap()), err)
+			}
+			// ParseStr decodes '+' as space, which is the same as "%20".
+			if result := ParseStrArray(query); !Identical(result, data) {
+				t.Fatalf("ParseStrArray(HttpBuildQuery(%s)) = %s", dumpOrderedMap(data.ToOrderedMap()), dumpOrderedMap(result.ToOrderedMap()))
+			}
+		}
+	}
+}
@@ -8,7 +8,7 @@ import (
 	"github.com/elliotchance/orderedmap/v2"
 )
 
-// Implode replicates the behavior of PHP 5.6's implode function in GO.
+// Implode replicates the behavior of PHP's implode function in GO.
 // This function concatenates the elements of an array into a single string using a specified separator.
 // For more information, see the [official PHP documentation].
 //
@@ -30,11 +30,19 @@ import (
 //     and used as the separator, with arg2 being the array to implode.
 //   - If neither arg1 nor arg2 is an array, the function returns an error.
 //
-// Non-string elements within the array are converted to strings using a ConvertToString function
+// 3. On PHP 8, arg1 must be the separator if arg2 is provided, like PHP 8 does. Otherwise the function
+// returns an error with the same message as the TypeError thrown by PHP.
+//
+// Before PHP 8, the error message is the same as the warning emitted by PHP, and the warning is also
+// emitted to the diagnostic handler of the Runtime. Passing the separator after the array emits
+// E_DEPRECATED on PHP 7.4.
+//
+// Non-string elements within the array are converted to strings using Runtime.ConvertToString
 // before joining.
 // Due to language differences between PHP and Go, the implode function support OrderedMap type from the [orderedmap library],
 // ensuring ordered map functionality. When imploding map types, please utilize the OrderedMap type from the [orderedmap library]
 // to maintain element order. If you use map type, not OrderedMap type, the order of the results cannot be guaranteed.
+// Array is supported as well, and it always maintains element order.
 //
 // reference:
 //   - implode: https://github.com/php/php-src/blob/php-5.6.40/ext/standard/string.c#L1229-L1269
@@ -46,32 +54,56 @@ import (
 //
 // [official PHP documentation]: https://www.php.net/manual/en/function.implode.php
 // [orderedmap library]: https://pkg.go.dev/github.com/elliotchance/orderedmap/v2
-func Implode(arg1 any, options ...any) (string, error) {
+func (r *Runtime) Implode(arg1 any, options ...any) (string, error) {
 	var delim string
 	var arr []any
 
-	// Check arg1 is one of array, slice, map, or ordered ap
-	isArg1CollectionType := isCollectionType(arg1)
-
-	// Check if options is not provided
-	if len(options) == 0 {
-		if !isArg1CollectionType {
-			return "", fmt.Errorf("argument must be one of array, slice, or ordered map, but got %v", reflect.TypeOf(arg1))
+	if r.Version >= PHP80 {
+		var err error
+		if delim, arr, err = r.implodeArgs(arg1, options...); err != nil {
+			return "", err
 		}
-		arr = aggregateValues(arg1)
 	} else {
-		arg2 := options[0]
-		// Check arg2 is one of array, slice, or ordered map
-		isArg2CollectionType := isCollectionType(arg2)
+		// Check arg1 is one of array, slice, map, or ordered ap
+		isArg1CollectionType := isCollectionType(arg1)
 
-		if isArg1CollectionType {
-			delim, _ = ConvertToString(arg2)
+		// Check if options is not provided
+		if len(options) == 0 {
+			if !isArg1CollectionType {
+				return "", r.warning(&TypeError{
+					Function:  "implode",
+					Param:     1,
+					ParamName: "pieces",
+					Expected:  "array",
+					Given:     zendZvalTypeName(arg1),
+					Message:   "implode(): Argument must be an array",
+				})
+			}
 			arr = aggregateValues(arg1)
-		} else if !isArg1CollectionType && isArg2CollectionType {
-			delim, _ = ConvertToString(arg1)
-			arr = aggregateValues(arg2)
 		} else {
-			return "", fmt.Errorf("invalid arguments passed, got %v, %v", reflect.TypeOf(arg1), reflect.TypeOf(arg2))
+			arg2 := options[0]
+			// Check arg2 is one of array, slice, or ordered map
+			isArg2CollectionType := isCollectionType(arg2)
+
+			if isArg1CollectionType {
+				if r.Version >= PHP74 {
+					r.emit(EDeprecated, "implode(): Passing the separator after the array is deprecated")
+				}
+				delim, _ = r.ConvertToString(arg2)
+				arr = aggregateValues(arg1)
+			} else if !isArg1CollectionType && isArg2CollectionType {
+				delim, _ = r.ConvertToString(arg1)
+				arr = aggregateValues(arg2)
+			} else {
+				return "", r.warning(&TypeError{
+					Function:  "implode",
+					Param:     2,
+					ParamName: "array",
+					Expected:  "?array",
+					Given:     zendZvalTypeName(arg2),
+					Message:   "implode(): Invalid arguments passed",
+				})
+			}
 		}
 	}
 
@@ -81,13 +113,11 @@ func Implode(arg1 any, options ...any) (string, error) {
 		return "", nil
 	}
 	for i, item := range arr {
-		str, err := ConvertToString(item)
-
+		str, err := r.ConvertToString(item)
 		if err != nil {
-			return "", fmt.Errorf("unsupported type in array : %v", reflect.TypeOf(item))
-		} else {
-			builder.WriteString(str)
+			return "", err
 		}
+		builder.WriteString(str)
 		if i < len(arr)-1 {
 			builder.WriteString(delim)
 		}
@@ -95,6 +125,71 @@ func Implode(arg1 any, options ...any) (string, error) {
 	return builder.String(), nil
 }
 
+// Implode works the same as Runtime.Implode of a Runtime emulating PHP 5.6.
+func Implode(arg1 any, options ...any) (string, error) {
+	return defaultRuntime.Implode(arg1, options...)
+}
+
+// implodeArgs parses the arguments of implode like PHP 8 does. PHP 8 no longer
+// accepts the separator after the array, and throws a TypeError for invalid
+// arguments instead of emitting a warning.
+//
+// Reference:
+//   - https://github.com/php/php-src/blob/php-8.0.0/ext/standard/string.c
+//   - https://github.com/php/php-src/blob/php-8.3.0/ext/standard/string.c
+func (r *Runtime) implodeArgs(arg1 any, options ...any) (string, []any, error) {
+	var pieces any
+	if len(options) > 0 {
+		pieces = options[0]
+	}
+
+	// Argument #1 is either an array or a string
+	isArg1CollectionType := isCollectionType(arg1)
+	var delim string
+	if !isArg1CollectionType {
+		var ok bool
+		if delim, ok = r.parseArgString(arg1); !ok {
+			return "", nil, implodeTypeError(1, "separator", "array|string", zendZvalTypeName(arg1),
+				"implode(): Argument #1 ($separator) must be of type array|string, %s given", zendZvalTypeName(arg1))
+		}
+	}
+
+	// Argument #2 is either an array or null
+	if pieces != nil && !isCollectionType(pieces) {
+		return "", nil, implodeTypeError(2, "array", "?array", zendZvalTypeName(pieces),
+			"implode(): Argument #2 ($array) must be of type ?array, %s given", zendZvalTypeName(pieces))
+	}
+
+	if pieces == nil {
+		if !isArg1CollectionType {
+			if r.Version >= PHP83 {
+				return "", nil, implodeTypeError(2, "array", "array", "null",
+					"implode(): If argument #1 ($separator) is of type string, argument #2 ($array) must be of type array, null given")
+			}
+			return "", nil, implodeTypeError(1, "pieces", "array", "string",
+				"implode(): Argument #1 ($pieces) must be of type array, string given")
+		}
+		return "", aggregateValues(arg1), nil
+	}
+	if isArg1CollectionType {
+		return "", nil, implodeTypeError(1, "separator", "string", "array",
+			"implode(): Argument #1 ($separator) must be of type string, array given")
+	}
+	return delim, aggregateValues(pieces), nil
+}
+
+// implodeTypeError returns TypeError thrown by implode for the given parameter.
+func implodeTypeError(param int, paramName, expected, given, format string, args ...any) *TypeError {
+	return &TypeError{
+		Function:  "implode",
+		Param:     param,
+		ParamName: paramName,
+		Expected:  expected,
+		Given:     given,
+		Message:   fmt.Sprintf(format, args...),
+	}
+}
+
 // isOrderedMap checks if the argument is an instance of ordered map
 func isOrderedMap(arg any) bool {
 	switch arg.(type) {
@@ -105,19 +200,25 @@ func isOrderedMap(arg any) bool {
 	}
 }
 
-// isCollectionType checks if the argument is either an array, a slice, a map or ordered map
+// isCollectionType checks if the argument is either an array, a slice, a map, ordered map or Array
 func isCollectionType(arg any) bool {
 	if arg == nil {
 		return false
 	}
+	if _, ok := asArray(arg); ok {
+		return true
+	}
 
 	argType := reflect.TypeOf(arg).Kind()
 	return isOrderedMap(arg) || argType == reflect.Slice || argType == reflect.Array || argType == reflect.Map
 }
 
 // aggregateValues extracts the stored value from different types of source:
-// ordered map, map, slice and array. It gathers there values into an arr and returns it.
+// Array, ordered map, map, slice and array. It gathers there values into an arr and returns it.
 func aggregateValues(source any) []any {
+	if arr, ok := asArray(source); ok {
+		return arr.Values()
+	}
 	if isOrderedMap(source) {
 		var om *orderedmap.OrderedMap[any, any]
 
@@ -151,3 +252,81 @@ func aggregateValues(source any) []any {
 		return arr
 	}
 }
+
+// countElements returns the number of elements of different types of source:
+// Array, ordered map, map, slice and array.
+func countElements(source any) int {
+	if arr, ok := asArray(source); ok {
+		return arr.Len()
+	}
+	switch om := source.(type) {
+	case orderedmap.OrderedMap[any, any]:
+		return om.Len()
+	case *orderedmap.OrderedMap[any, any]:
+		return om.Len()
+	default:
+		return reflect.ValueOf(source).Len()
+	}
+}
+
+// arrayEntry is a key-value pair of an array. Key is either int or string.
+type arrayEntry struct {
+	key   any
+	value any
+}
+
+// aggregateEntries extracts the stored key-value pairs from different types of
+// source: Array, ordered map, map, slice and array. Keys are normalized to
+// either int or string like PHP's array keys, and slices and arrays use their
+// indices as keys.
+func aggregateEntries(source any) []arrayEntry {
+	if arr, ok := asArray(source); ok {
+		entries := make([]arrayEntry, 0, arr.Len())
+		arr.Range(func(key, value any) bool {
+			entries = append(entries, arrayEntry{key, value})
+			return true
+		})
+		return entries
+	}
+	if isOrderedMap(source) {
+		var om *orderedmap.OrderedMap[any, any]
+
+		switch tmp := source.(type) {
+		case orderedmap.OrderedMap[any, any]:
+			// If source is an OrderedMap struct, use address of source
+			om = &tmp
+		case *orderedmap.OrderedMap[any, any]:
+			om = tmp
+		}
+
+		entries := make([]arrayEntry, 0, om.Len())
+		for el := om.Front(); el != nil; el = el.Next() {
+			entries = append(entries, arrayEntry{arrayKey(el.Key), el.Value})
+		}
+		return entries
+	}
+
+	v := reflect.ValueOf(source)
+	entries := make([]arrayEntry, 0, v.Len())
+	switch v.Kind() {
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			entries = append(entries, arrayEntry{arrayKey(iter.Key().Interface()), iter.Value().Interface()})
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			entries = append(entries, arrayEntry{i, v.Index(i).Interface()})
+		}
+	}
+	return entries
+}
+
+// arrayKey normalizes the given key to either int or string, like PHP does for
+// array keys. Keys which can not be normalized are returned as is.
+func arrayKey(key any) any {
+	if k, err := normalizeArrayKey(key); err == nil {
+		return k
+	}
+	return key
+}
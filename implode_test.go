@@ -1,6 +1,7 @@
 package gophplib
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
 	"strings"
@@ -148,13 +149,13 @@ func ExampleImplode_error() {
 	fmt.Println(Implode("foo", []any{Dog{"choco", 5}, Cat{"nabi", 3}}))
 
 	// Output:
-	//  invalid arguments passed, got string, *os.File
-	//  argument must be one of array, slice, or ordered map, but got string
-	//  invalid arguments passed, got string, int
-	//  invalid arguments passed, got string, <nil>
-	//  invalid arguments passed, got int, string
-	//  invalid arguments passed, got <nil>, string
-	//  unsupported type in array : gophplib.Dog
+	//  implode(): Invalid arguments passed
+	//  implode(): Argument must be an array
+	//  implode(): Invalid arguments passed
+	//  implode(): Invalid arguments passed
+	//  implode(): Invalid arguments passed
+	//  implode(): Invalid arguments passed
+	//  Object of class Dog could not be converted to string
 }
 
 func TestImplode(t *testing.T) {
@@ -205,7 +206,7 @@ func TestImplode(t *testing.T) {
 					if tc.string != "" {
 						t.Errorf("%s: expected : %s, got error %s", testName, tc.string, err.Error())
 					} else {
-						expectedErr := fmt.Errorf("argument must be one of array, slice, or ordered map, but got %v", reflect.TypeOf(tc.arg1))
+						expectedErr := fmt.Errorf("implode(): Argument must be an array")
 						if err.Error() != expectedErr.Error() {
 							t.Errorf("%s: expected error : %s, got %s", testName, expectedErr.Error(), err.Error())
 						}
@@ -221,7 +222,7 @@ func TestImplode(t *testing.T) {
 					if tc.string != "" {
 						t.Errorf("%s: expected : %s, got error %s", testName, tc.string, err.Error())
 					} else {
-						expectedErr := fmt.Errorf("invalid arguments passed, got %v, %v", reflect.TypeOf(tc.arg1), reflect.TypeOf(tc.arg2))
+						expectedErr := fmt.Errorf("implode(): Invalid arguments passed")
 						if err.Error() != expectedErr.Error() {
 							t.Errorf("%s: expected error : %s, got %s", testName, expectedErr.Error(), err.Error())
 						}
@@ -244,8 +245,8 @@ func TestImplode(t *testing.T) {
 	t.Run(testName, func(t *testing.T) {
 		result, err := Implode(typeErrCase.arg1, typeErrCase.arg2)
 		if err != nil {
-			if !strings.Contains(err.Error(), "unsupported type in array") {
-				t.Errorf("%s: expected error : unsupported type in array, bug got %s", testName, err.Error())
+			if !errors.Is(err, ErrError) {
+				t.Errorf("%s: expected Error, but got %s", testName, err.Error())
 			}
 		} else {
 			t.Errorf("%s: error, but got %v", testName, result)
@@ -253,6 +254,51 @@ func TestImplode(t *testing.T) {
 	})
 }
 
+// TestImplodePHP8 tests implode of PHP 8, which throws TypeError for invalid
+// arguments instead of emitting a warning.
+//
+// References:
+//   - https://github.com/php/php-src/blob/php-8.0.0/ext/standard/tests/strings/implode1.phpt
+func TestImplodePHP8(t *testing.T) {
+	testCases := []struct {
+		args     []any
+		expected string
+		err      string
+	}{
+		{[]any{", ", []int{1, 2}}, "1, 2", ""},
+		{[]any{[]string{"a", "b"}}, "ab", ""},
+		{[]any{[]string{"a", "b"}, nil}, "ab", ""},
+		{[]any{1, []string{"a", "b"}}, "a1b", ""},
+		{[]any{nil, []string{"a", "b"}}, "ab", ""},
+		{[]any{Cat{"nabi", 3}, []string{"a", "b"}}, "aname is nabi and 3 years oldb", ""},
+		{[]any{[]string{"a", "b"}, ", "}, "", "implode(): Argument #2 ($array) must be of type ?array, string given"},
+		{[]any{"glue", 1234}, "", "implode(): Argument #2 ($array) must be of type ?array, int given"},
+		{[]any{[]string{"a"}, []string{"b"}}, "", "implode(): Argument #1 ($separator) must be of type string, array given"},
+		{[]any{Dog{"choco", 5}, []string{"a"}}, "", "implode(): Argument #1 ($separator) must be of type array|string, Dog given"},
+		{[]any{"glue"}, "", "implode(): Argument #1 ($pieces) must be of type array, string given"},
+		{[]any{"glue", nil}, "", "implode(): Argument #1 ($pieces) must be of type array, string given"},
+		{[]any{", ", []any{Dog{"choco", 5}}}, "", "Object of class Dog could not be converted to string"},
+	}
+
+	r := NewRuntime(PHP80)
+	for _, tc := range testCases {
+		result, err := r.Implode(tc.args[0], tc.args[1:]...)
+		if tc.err != "" {
+			if err == nil || err.Error() != tc.err {
+				t.Errorf("Implode(%#v) = (%q, %v); want error %q", tc.args, result, err, tc.err)
+			}
+		} else if err != nil || result != tc.expected {
+			t.Errorf("Implode(%#v) = (%q, %v); want %q", tc.args, result, err, tc.expected)
+		}
+	}
+
+	_, err := NewRuntime(PHP83).Implode("glue")
+	expected := "implode(): If argument #1 ($separator) is of type string, argument #2 ($array) must be of type array, null given"
+	if err == nil || err.Error() != expected {
+		t.Errorf("Implode on PHP 8.3 returned error %v; want %q", err, expected)
+	}
+}
+
 func BenchmarkImplode(b *testing.B) {
 	// Initialization
 	var (
@@ -0,0 +1,107 @@
+package gophplib
+
+// INI holds the PHP INI settings which affect the behavior of the functions
+// of a Runtime.
+//
+// The zero value of each field except RequestOrder means the default value of
+// the version of the Runtime, so a Runtime whose INI is not set, or only
+// partially set, still behaves like PHP with the default php.ini. To emulate
+// the settings which PHP accepts as zero, use their equivalents noted below.
+type INI struct {
+	// Precision is the number of significant digits used when converting
+	// floats to strings. It is the "precision" INI setting, and -1 means the
+	// shortest string which round-trips, like PHP 7.1 and later. PHP treats
+	// 0 the same as 1.
+	Precision int
+
+	// SerializePrecision is the number of significant digits used when
+	// serializing floats, such as by var_export. It is the
+	// "serialize_precision" INI setting, and -1 means the shortest string
+	// which round-trips, like Precision. PHP treats 0 the same as 1.
+	SerializePrecision int
+
+	// MaxInputVars is the maximum number of variables parsed from an input,
+	// such as a query string. Variables after the limit are dropped. It is
+	// the "max_input_vars" INI setting. Negative values drop all the
+	// variables, like 0 in PHP.
+	MaxInputVars int
+
+	// MaxInputNestingLevel is the maximum depth of nested arrays in the
+	// names of variables parsed from an input. Variables which exceed the
+	// limit are dropped along with the other variables of the same name. It
+	// is the "max_input_nesting_level" INI setting. Negative values drop all
+	// the variables with brackets, like 0 in PHP.
+	MaxInputNestingLevel int
+
+	// ArgSeparatorInput is the set of characters used to separate variables
+	// when parsing an input, such as a query string. Each character is a
+	// separator by itself. It is the "arg_separator.input" INI setting.
+	ArgSeparatorInput string
+
+	// ArgSeparatorOutput is the separator used to separate variables when
+	// building a query string, such as by HttpBuildQuery. It is the
+	// "arg_separator.output" INI setting.
+	ArgSeparatorOutput string
+
+	// VariablesOrder is the order of the superglobals built from a request,
+	// such as "GPC" for $_GET, $_POST and $_COOKIE. The superglobals not in
+	// it are left empty. It is the "variables_order" INI setting. Use "S" to
+	// build none of them, like an empty string in PHP.
+	VariablesOrder string
+
+	// RequestOrder is the order of the superglobals merged into $_REQUEST,
+	// such as "GP" for $_GET and $_POST. It is the "request_order" INI
+	// setting, and an empty string means VariablesOrder.
+	RequestOrder string
+}
+
+// DefaultINI returns the default INI settings of the given PHP version, which
+// are the values used when php.ini does not set them.
+//
+// Reference:
+//   - https://github.com/php/php-src/blob/php-5.6.40/main/main.c
+//   - https://github.com/php/php-src/blob/php-7.1.0/main/main.c
+func DefaultINI(version Version) INI {
+	ini := INI{
+		Precision:            14,
+		SerializePrecision:   17,
+		MaxInputVars:         1000,
+		MaxInputNestingLevel: 64,
+		ArgSeparatorInput:    "&",
+		ArgSeparatorOutput:   "&",
+		VariablesOrder:       "EGPCS",
+	}
+	if version >= PHP71 {
+		ini.SerializePrecision = -1
+	}
+	return ini
+}
+
+// ini returns the INI settings of the runtime, whose zero fields are replaced
+// with the default values of its version.
+func (r *Runtime) ini() INI {
+	ini := r.INI
+	def := DefaultINI(r.Version)
+	if ini.Precision == 0 {
+		ini.Precision = def.Precision
+	}
+	if ini.SerializePrecision == 0 {
+		ini.SerializePrecision = def.SerializePrecision
+	}
+	if ini.MaxInputVars == 0 {
+		ini.MaxInputVars = def.MaxInputVars
+	}
+	if ini.MaxInputNestingLevel == 0 {
+		ini.MaxInputNestingLevel = def.MaxInputNestingLevel
+	}
+	if ini.ArgSeparatorInput == "" {
+		ini.ArgSeparatorInput = def.ArgSeparatorInput
+	}
+	if ini.ArgSeparatorOutput == "" {
+		ini.ArgSeparatorOutput = def.ArgSeparatorOutput
+	}
+	if ini.VariablesOrder == "" {
+		ini.VariablesOrder = def.VariablesOrder
+	}
+	return ini
+}
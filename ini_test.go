@@ -0,0 +1,48 @@
+package gophplib
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestZeroINI(t *testing.T) {
+	for _, version := range []Version{PHP56, PHP80} {
+		r := &Runtime{Version: version}
+		if ini, def := r.ini(), DefaultINI(version); ini != def {
+			t.Errorf("ini() on %d = %+v; want %+v", version, ini, def)
+		}
+
+		var diags []Diagnostic
+		r.DiagnosticHandler = func(d Diagnostic) { diags = append(diags, d) }
+
+		if result := r.ParseStrArray("a=1&b[x]=2"); !Identical(result, arrayOf("a", "1", "b", arrayOf("x", "2"))) {
+			t.Errorf("ParseStrArray on %d = %s", version, dumpOrderedMap(result.ToOrderedMap()))
+		}
+		if s, err := r.ConvertToString(1.5); err != nil || s != "1.5" {
+			t.Errorf("ConvertToString(1.5) on %d = (%q, %v)", version, s, err)
+		}
+		var b strings.Builder
+		if r.VarExport(&b, 0.1); (version < PHP71 && b.String() != "0.10000000000000001") || (version >= PHP71 && b.String() != "0.1") {
+			t.Errorf("VarExport(0.1) on %d = %q", version, b.String())
+		}
+		var v struct{ A []int }
+		if err := r.ParseStrInto("A[]=1&A[]=2", &v); err != nil || len(v.A) != 2 {
+			t.Errorf("ParseStrInto on %d = (%v, %v)", version, v, err)
+		}
+		if s, _ := r.HttpBuildQuery([]int{1, 2}, "", "", 0); s != "0=1&1=2" {
+			t.Errorf("HttpBuildQuery on %d = %q", version, s)
+		}
+		if len(diags) != 0 {
+			t.Errorf("emitted %v on %d", diags, version)
+		}
+	}
+
+	// Only the zero fields are replaced
+	r := &Runtime{Version: PHP80, INI: INI{Precision: 3, MaxInputVars: 1}}
+	if s, _ := r.ConvertToString(1.2345); s != "1.23" {
+		t.Errorf("ConvertToString(1.2345) = %q; want 1.23", s)
+	}
+	if n := r.ParseStrArray("a[]=1&b=2").Len(); n != 1 {
+		t.Errorf("got %d variables; want 1", n)
+	}
+}
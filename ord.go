@@ -1,20 +1,16 @@
 package gophplib
 
-import (
-	"fmt"
-	"reflect"
-)
-
-// Ord is a ported functions that works exactly the same as PHP 5.6's ord function.
+// Ord is a ported functions that works exactly the same as PHP's ord function.
 // In PHP 5.6, when the ord() function is used with a data type other
 // than a string, it automatically converts the given variable into a string
 // before processing it. To achieve the same behavior in Go,
-// this function converts an argument to string using the zendParseArgAsString() function.
+// this function converts an argument to string using Runtime.ParseParameters.
 // For more information, see the [official PHP documentation].
 //
 // This function returns error if given argument is not one of following:
-// string, int, int64, float64, bool, nil, and any type which does not implement
-// interface { toString() string }.
+// string, int, int64, float64, bool, nil, and any type which implements
+// Stringable. The error message is the same as the warning emitted by PHP, or the TypeError thrown
+// by PHP 8.
 //
 // Reference :
 //   - https://github.com/php/php-src/blob/php-5.6.40/ext/standard/string.c#L2666-L2676
@@ -25,11 +21,11 @@ import (
 //   - https://github.com/php/php-src/blob/php-5.6.40/ext/standard/tests/strings/ord_variation1.phpt
 //
 // [official PHP documentation]: https://www.php.net/manual/en/function.ord.php
-func Ord(character any) (byte, error) {
+func (r *Runtime) Ord(character any) (byte, error) {
 	// Convert a character to string
-	characterString, err := zendParseArgAsString(character)
-	if err != nil {
-		return 0, fmt.Errorf("unsupported type : %s", reflect.TypeOf(character))
+	var characterString string
+	if err := r.ParseParameters(Function{"ord", []string{"character"}}, []any{character}, "s", &characterString); err != nil {
+		return 0, err
 	}
 
 	// Check if the characterString is not empty
@@ -40,3 +36,8 @@ func Ord(character any) (byte, error) {
 	// Return for empty strings
 	return 0, nil
 }
+
+// Ord works the same as Runtime.Ord of a Runtime emulating PHP 5.6.
+func Ord(character any) (byte, error) {
+	return defaultRuntime.Ord(character)
+}
@@ -58,7 +58,7 @@ func ExampleOrd_variation() {
 	fmt.Println(Ord(unsetVariable))
 
 	// Output:
-	// 0 unsupported type : []int
+	// 0 ord() expects parameter 1 to be string, array given
 	// 0 <nil>
 	// 0 <nil>
 	// 0 <nil>
@@ -88,7 +88,7 @@ func TestOrd(t *testing.T) {
 		t.Run(testName, func(t *testing.T) {
 			result, err := Ord(tc.any)
 			if err != nil {
-				byteErr := fmt.Errorf("unsupported type : %s", reflect.TypeOf(tc.any))
+				byteErr := fmt.Errorf("ord() expects parameter 1 to be string, %s given", defaultRuntime.zvalTypeName(tc.any))
 				if err.Error() != byteErr.Error() {
 					t.Errorf("%s: byte error : %s, got %s", testName, byteErr, err)
 				}
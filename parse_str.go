@@ -15,6 +15,21 @@ import (
 // the returned orderedmap.OrderedMap are either string or "orderedmap.OrderedMap[string | int]RetVal".
 // For more information about orderedmap libaray, see the [orderedmap documentation].
 //
+// Keys and values are decoded with Urldecode, so they are the raw bytes of the input just like PHP,
+// even if they are not valid UTF-8. See ParseStrWithOptions to replace invalid UTF-8 sequences. Like
+// PHP, a key ends at its first NUL byte, such as "a" for "a%00b=1", while values may contain NUL bytes.
+//
+// The input is parsed within the limits of the INI settings of the Runtime, like PHP does:
+//   - Only the first MaxInputVars variables are parsed, and the rest of the input is dropped. Empty pairs
+//     between consecutive '&' are not counted, but pairs without names such as "=1" are. "Input variables
+//     exceeded 1000. To increase the limit change max_input_vars in php.ini." is emitted as E_WARNING in this
+//     case.
+//   - If the name of a variable has more than MaxInputNestingLevel levels of brackets, such as "a[][][]"
+//     with the limit 2, the variable is dropped, and the variables parsed so far with the same base name
+//     ("a" in this case) are deleted as well. "Input variable nesting level exceeded 64. To increase the
+//     limit change max_input_nesting_level in php.ini." is emitted as E_WARNING in this case. Note that PHP
+//     emits this warning only when display_errors is off, to avoid information disclosure.
+//
 // Reference:
 //   - https://www.php.net/manual/en/function.parse-str.php
 //   - https://github.com/php/php-src/blob/php-5.6.40/main/php_variables.c#L450-L496
@@ -22,23 +37,149 @@ import (
 //
 // [official PHP documentation]: https://www.php.net/manual/en/function.parse-str.php
 // [orderedmap documentation]: https://pkg.go.dev/github.com/elliotchance/orderedmap/v2@v2.2.0
+func (r *Runtime) ParseStr(input string) orderedmap.OrderedMap[any, any] {
+	return r.ParseStrArray(input).ToOrderedMap()
+}
+
+// ParseStr works the same as Runtime.ParseStr of a Runtime emulating PHP 5.6.
 func ParseStr(input string) orderedmap.OrderedMap[any, any] {
-	ret := newPHPArray()
+	return defaultRuntime.ParseStr(input)
+}
+
+// ParseStrArray works the same as ParseStr, except that it returns an Array
+// instead of orderedmap.OrderedMap. Nested arrays are returned as *Array, and
+// all keys are either string or int.
+func (r *Runtime) ParseStrArray(input string) *Array {
+	return r.ParseStrWithOptions(input, ParseStrOptions{})
+}
+
+// ParseStrArray works the same as Runtime.ParseStrArray of a Runtime emulating PHP 5.6.
+func ParseStrArray(input string) *Array {
+	return defaultRuntime.ParseStrArray(input)
+}
+
+// ParseStrOptions configures ParseStrWithOptions. The zero value parses the
+// input in the same way as ParseStr.
+type ParseStrOptions struct {
+	// ValidUTF8 makes the keys and the values to be decoded with
+	// UrldecodeValidUTF8 instead of Urldecode, so that they are always valid
+	// UTF-8. PHP never does this, so the result may differ from PHP's.
+	ValidUTF8 bool
+
+	// Separators is the set of characters which separate variables, like
+	// the "arg_separator.input" INI setting. For example, "&;" splits the
+	// input on both '&' and ';'. If it is empty, ArgSeparatorInput of the INI
+	// settings of the Runtime is used. It is ignored if Cookie is true.
+	Separators string
+
+	// Cookie makes the input to be parsed as the value of a Cookie header,
+	// like PHP does when it builds $_COOKIE:
+	//   - Variables are separated by ';', and the whitespaces in front of
+	//     the names are skipped.
+	//   - Variables without names such as "=foo" are skipped, and not
+	//     counted against MaxInputVars.
+	//   - Since PHP 8, names are not decoded, and values are decoded with
+	//     Rawurldecode, so '+' is left as is. Before PHP 8, both are decoded
+	//     with Urldecode like ParseStr. Note that PHP 7.2.34, 7.3.23 and
+	//     7.4.11 also stopped decoding names as a security fix, which is not
+	//     emulated.
+	//   - If the same name appears more than once, the first one wins. Note
+	//     that it applies only to the top-level names, so "a[x]=1; a[x]=2"
+	//     results in "2" just like ParseStr.
+	Cookie bool
+}
+
+// ParseStrWithOptions works the same as ParseStrArray, except that the input
+// is parsed with the given options.
+//
+// Reference:
+//   - https://github.com/php/php-src/blob/php-8.3.0/main/php_variables.c#L473-L568
+func (r *Runtime) ParseStrWithOptions(input string, options ParseStrOptions) *Array {
+	return r.treatData(input, options, "parse_str()")
+}
 
-	// Split input with '&'
-	pairs := strings.Split(input, "&")
+// ParseStrWithOptions works the same as Runtime.ParseStrWithOptions of a Runtime emulating PHP 5.6.
+func ParseStrWithOptions(input string, options ParseStrOptions) *Array {
+	return defaultRuntime.ParseStrWithOptions(input, options)
+}
 
+// treatData is a ported function that works exactly the same as PHP's
+// php_default_treat_data function, which parses the input for ParseStr, $_GET
+// and $_COOKIE. caller is the name of the function in the diagnostics, which
+// is "Unknown" while PHP builds the superglobals.
+func (r *Runtime) treatData(input string, options ParseStrOptions, caller string) *Array {
+	ini := r.ini()
+	decodeKey, decodeValue := Urldecode, Urldecode
+	separators := options.Separators
+	if separators == "" {
+		separators = ini.ArgSeparatorInput
+	}
+	if options.Cookie {
+		if r.Version >= PHP80 {
+			decodeKey = func(s string) string { return s }
+			decodeValue = func(s string) string { return urldecode(s, false) }
+		}
+		separators = ";"
+	}
+	if options.ValidUTF8 {
+		decodeKey = validUTF8(decodeKey)
+		decodeValue = validUTF8(decodeValue)
+	}
+
+	ret := NewArray()
+
+	// Split input with separators, skipping empty pairs like strtok does
+	pairs := strtok(input, separators)
+
+	count := 0
 	for _, pair := range pairs {
-		// Skip empty pair
-		if pair == "" {
-			continue
+		if options.Cookie {
+			// Remove leading spaces from cookie names, needed for
+			// multi-cookie header where ; can be followed by a space
+			for pair != "" && isAsciiWhitespace(pair[0]) {
+				pair = pair[1:]
+			}
+			if pair == "" || pair[0] == '=' {
+				continue
+			}
+		}
+
+		count++
+		if count > ini.MaxInputVars {
+			r.emit(EWarning, "%s: Input variables exceeded %d. To increase the limit change max_input_vars in php.ini.", caller, ini.MaxInputVars)
+			break
 		}
 
 		// Cut pair with '='
 		key, value, _ := strings.Cut(pair, "=")
-		registerVariableSafe(Urldecode(key), Urldecode(value), ret)
+		r.registerVariableSafe(decodeKey(key), decodeValue(value), ret, options.Cookie, caller)
+	}
+	return ret
+}
+
+// strtok splits the input into the tokens separated by any of the bytes of
+// separators, like repeated calls of C's strtok. Empty tokens are skipped.
+func strtok(input, separators string) []string {
+	var tokens []string
+	start := 0
+	for i := 0; i <= len(input); i++ {
+		if i < len(input) && strings.IndexByte(separators, input[i]) == -1 {
+			continue
+		}
+		if start < i {
+			tokens = append(tokens, input[start:i])
+		}
+		start = i + 1
+	}
+	return tokens
+}
+
+// validUTF8 wraps the given decode function so that invalid UTF-8 sequences
+// of its result are replaced with U+FFFD.
+func validUTF8(decode func(string) string) func(string) string {
+	return func(s string) string {
+		return strings.ToValidUTF8(decode(s), "\uFFFD")
 	}
-	return ret.intoMap()
 }
 
 // registerVariableSafe is a ported function that works exactly the same as
@@ -47,12 +188,23 @@ func ParseStr(input string) orderedmap.OrderedMap[any, any] {
 // Reference:
 //   - https://github.com/php/php-src/blob/php-5.6.40/main/php_variables.c#L59-L233
 //   - https://github.com/php/php-src/blob/php-8.3.0/main/php_variables.c#L90-L314
-func registerVariableSafe(key, value string, track *phpSymtable) {
+//
+// If cookie is true, the top-level variables which already exist are not
+// overwritten, like PHP does for $_COOKIE. caller is the name of the function
+// in the diagnostics, like treatData.
+func (r *Runtime) registerVariableSafe(key, value string, track *Array, cookie bool, caller string) {
 	// NOTE: key is "var_name", value is "val", track is "track_vars_array" in
 	// below PHP version's function signature.
 	//
 	// PHPAPI void php_register_variable_ex(const char *var_name, zval *val, zval *track_vars_array)
 
+	root := track
+
+	// the variable name is a C string, so it ends at the first NUL byte
+	if i := strings.IndexByte(key, 0); i != -1 {
+		key = key[:i]
+	}
+
 	// ignore leading spaces in the variable name
 	key = strings.TrimLeft(key, " ")
 
@@ -81,18 +233,32 @@ func registerVariableSafe(key, value string, track *phpSymtable) {
 	index := key_new
 
 	if is_array {
-		// We do not perform max nesting level check here
+		base := phpNumericOrString(key_new)
+		maxNestingLevel := r.ini().MaxInputNestingLevel
+		nestLevel := 0
 		idx := 0 // idx is offset of "ip" pointer in the original PHP codes.
 		for {
+			nestLevel++
+			if nestLevel > maxNestingLevel {
+				// too many levels of nesting
+				root.Delete(base)
+				r.emit(EWarning, "%s: Input variable nesting level exceeded %d. To increase the limit change max_input_nesting_level in php.ini.", caller, maxNestingLevel)
+				return
+			}
 			idx++
 			idx_s := idx // idx_next is "index_s" in the original PHP codes.
-			if isAsciiWhitespace(index_slice[idx]) {
+			// NOTE: idx may reach the end of index_slice, where the original
+			// PHP codes read the NUL terminator.
+			if idx < len(index_slice) && isAsciiWhitespace(index_slice[idx]) {
 				idx++
 			}
-			if index_slice[idx] == ']' {
+			if idx < len(index_slice) && index_slice[idx] == ']' {
 				idx_s = -1
 			} else {
-				ret := bytes.IndexByte(index_slice[idx:], ']')
+				ret := -1
+				if idx < len(index_slice) {
+					ret = bytes.IndexByte(index_slice[idx:], ']')
+				}
 				if ret == -1 {
 					// not an index; un-terminate the var name
 					index_slice[idx_s-1] = '_'
@@ -108,23 +274,23 @@ func registerVariableSafe(key, value string, track *phpSymtable) {
 				idx += ret
 			}
 
-			var subdict *phpSymtable
+			var subdict *Array
 			if index == nil {
-				subdict = newPHPArray()
-				track.setNext(subdict)
+				subdict = NewArray()
+				track.Append(subdict)
 			} else {
-				value, ok := track.get(index)
+				value, ok := track.Get(string(index))
 				if !ok {
-					subdict = newPHPArray()
-					track.set(index, subdict)
+					subdict = NewArray()
+					track.set(phpNumericOrString(index), subdict)
 				} else {
 					// References for origianl PHP codes of here:
 					//   - https://www.phpinternalsbook.com/php7/zvals/memory_management.html
 					//   - https://www.phpinternalsbook.com/php7/zvals/basic_structure.html
-					underlying, ok := value.(*phpSymtable)
+					underlying, ok := value.(*Array)
 					if !ok {
-						subdict = newPHPArray()
-						track.set(index, subdict)
+						subdict = NewArray()
+						track.set(phpNumericOrString(index), subdict)
 					} else {
 						subdict = underlying
 					}
@@ -147,69 +313,16 @@ func registerVariableSafe(key, value string, track *phpSymtable) {
 	}
 plain_var:
 	if index == nil {
-		track.setNext(value)
+		track.Append(value)
 	} else {
-		track.set(index, value)
-	}
-}
-
-// phpSymtable is a orderedmap.OrderedMap[any, any] which behaves like PHP's array. It maintains
-// internal next (i.e. nNextFreeElement of PHP) state and it automatically
-// converts numeric string keys to integer keys.
-type phpSymtable struct {
-	next int
-	// Key is either string or int.
-	// Value is either string or *phpSymtable.
-	d orderedmap.OrderedMap[any, any]
-}
-
-func newPHPArray() *phpSymtable {
-	return &phpSymtable{
-		next: 0,
-		d:    *orderedmap.NewOrderedMap[any, any](),
-	}
-}
-
-// It returns an orderedmap.OrderedMap[any, any] whose keys are either string or int, and whose
-// values (RetVal) are either string or "orderedmap.OrderedMap[string | int, RetVal]".
-func (p *phpSymtable) intoMap() orderedmap.OrderedMap[any, any] {
-	ret := *orderedmap.NewOrderedMap[any, any]()
-	for el := p.d.Front(); el != nil; el = el.Next() {
-		key := el.Key
-		value := el.Value
-		if sub, ok := el.Value.(*phpSymtable); ok {
-			ret.Set(key, sub.intoMap())
-		} else {
-			ret.Set(key, value)
+		// Ignore cookies with the same name
+		if cookie && track == root {
+			if _, ok := track.Get(string(index)); ok {
+				return
+			}
 		}
+		track.set(phpNumericOrString(index), value)
 	}
-	return ret
-}
-
-func (p *phpSymtable) get(key []byte) (any, bool) {
-	k := phpNumericOrString(key)
-	v, ok := p.d.Get(k)
-	return v, ok
-}
-
-func (p *phpSymtable) set(key []byte, value any) {
-	k := phpNumericOrString(key)
-	if numeric, ok := k.(int); ok {
-		p.next = maxInt(p.next, numeric+1)
-	}
-	p.d.Set(k, value)
-}
-
-func (p *phpSymtable) setNext(value any) {
-	p.d.Set(p.next, value)
-	p.next++
-}
-
-func maxInt(a, b int) int {
-	if a > b {
-		return a
-	}
-	return b
 }
 
 func phpNumericOrString(input []byte) any {
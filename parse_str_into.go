@@ -0,0 +1,277 @@
+package gophplib
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+)
+
+// ParseStrError is returned by ParseStrInto when a variable can not be
+// decoded into the destination.
+type ParseStrError struct {
+	// Key is the full PHP key of the variable, such as "items[0][name]".
+	Key string
+	// Err is the reason why the variable can not be decoded. It is
+	// *TypeError if the variable has a wrong type, and *ValueError if it is
+	// out of the range of the destination.
+	Err error
+}
+
+func (e *ParseStrError) Error() string {
+	if e.Key == "" {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("%s: %v", e.Key, e.Err)
+}
+
+func (e *ParseStrError) Unwrap() error {
+	return e.Err
+}
+
+// ParseStrInto parses the input like ParseStrArray, and decodes the result
+// into the value pointed to by dst, which is usually a pointer to a struct.
+//
+// Variables are decoded into the Go values as follows:
+//   - Structs receive the variables whose names are the same as the "php" tags
+//     of their exported fields, or the names of the fields if they have no
+//     tags. Fields tagged with "-" are ignored. Variables without matching
+//     fields are ignored, and fields without matching variables are left
+//     untouched.
+//   - Slices receive the arrays whose keys are non-negative integers, so both
+//     "a[]=x&a[]=y" and "a[1]=y&a[0]=x" are decoded into []string{"x", "y"}.
+//     Elements are placed at their keys, and the missing ones are zero
+//     values. Keys must be less than the MaxInputVars INI setting, so that a
+//     single variable like "a[99999999]" can not allocate a huge slice. Go
+//     arrays receive them as well, whose keys must be less than their length.
+//   - Maps receive the arrays, whose keys are converted to the key type of
+//     the maps like the values.
+//   - Pointers are allocated if they are nil.
+//   - Types which implement encoding.TextUnmarshaler receive the strings.
+//   - Strings receive the strings as is, and []byte receives them as well.
+//   - Integers, floats and bools receive the strings converted with the same
+//     rules as the arguments of PHP's internal functions in coercive typing
+//     mode of the PHP version of the Runtime, like ParseParameters. For
+//     example, "1e3" is 1000 for int, "abc" is an error for int, and "" and
+//     "0" are false for bool while others are true.
+//     Diagnostics like "A non-numeric value encountered" for "12abc" are
+//     emitted to the diagnostic handler of the Runtime.
+//   - Interfaces without methods receive the values of ParseStrArray as is,
+//     which are either string or *Array.
+//
+// If a variable can not be decoded, it returns *ParseStrError with the full
+// PHP key of the variable, and dst may be partially modified. If dst is not a
+// non-nil pointer, it returns *ParseStrError with an empty key, which wraps
+// *TypeError.
+func (r *Runtime) ParseStrInto(input string, dst any) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Pointer || v.IsNil() {
+		return &ParseStrError{Err: &TypeError{Message: fmt.Sprintf("ParseStrInto: dst must be a non-nil pointer, %T given", dst)}}
+	}
+	return r.decodeVariable("", r.ParseStrArray(input), v.Elem())
+}
+
+// ParseStrInto works the same as Runtime.ParseStrInto of a Runtime emulating PHP 5.6.
+func ParseStrInto(input string, dst any) error {
+	return defaultRuntime.ParseStrInto(input, dst)
+}
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+// decodeVariable decodes src, which is either string, int or *Array, into
+// dst. key is the PHP key of src, which is empty for the top-level array.
+func (r *Runtime) decodeVariable(key string, src any, dst reflect.Value) error {
+	if dst.Kind() == reflect.Pointer {
+		if dst.IsNil() {
+			dst.Set(reflect.New(dst.Type().Elem()))
+		}
+		return r.decodeVariable(key, src, dst.Elem())
+	}
+
+	if dst.CanAddr() && dst.Addr().Type().Implements(textUnmarshalerType) {
+		str, ok := src.(string)
+		if !ok {
+			return decodeTypeError(key, "string", src)
+		}
+		if err := dst.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(str)); err != nil {
+			return &ParseStrError{key, err}
+		}
+		return nil
+	}
+
+	switch dst.Kind() {
+	case reflect.Interface:
+		if dst.NumMethod() != 0 {
+			return &ParseStrError{key, &UnsupportedTypeError{dst.Interface()}}
+		}
+		dst.Set(reflect.ValueOf(src))
+		return nil
+	case reflect.Struct:
+		arr, ok := src.(*Array)
+		if !ok {
+			return decodeTypeError(key, "array", src)
+		}
+		t := dst.Type()
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			name, ok := f.Tag.Lookup("php")
+			if !f.IsExported() || name == "-" {
+				continue
+			}
+			if !ok || name == "" {
+				name = f.Name
+			}
+			value, ok := arr.Get(name)
+			if !ok {
+				continue
+			}
+			if err := r.decodeVariable(subKey(key, name), value, dst.Field(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Map:
+		arr, ok := src.(*Array)
+		if !ok {
+			return decodeTypeError(key, "array", src)
+		}
+		if dst.IsNil() {
+			dst.Set(reflect.MakeMapWithSize(dst.Type(), arr.Len()))
+		}
+		var err error
+		arr.Range(func(k, value any) bool {
+			mapKey := reflect.New(dst.Type().Key()).Elem()
+			if err = r.decodeVariable(subKey(key, k), k, mapKey); err != nil {
+				return false
+			}
+			elem := reflect.New(dst.Type().Elem()).Elem()
+			if err = r.decodeVariable(subKey(key, k), value, elem); err != nil {
+				return false
+			}
+			dst.SetMapIndex(mapKey, elem)
+			return true
+		})
+		return err
+	case reflect.Slice, reflect.Array:
+		if str, ok := src.(string); ok && dst.Kind() == reflect.Slice && dst.Type().Elem().Kind() == reflect.Uint8 {
+			dst.SetBytes([]byte(str))
+			return nil
+		}
+		arr, ok := src.(*Array)
+		if !ok {
+			return decodeTypeError(key, "array", src)
+		}
+		return r.decodeList(key, arr, dst)
+	default:
+		if err := r.decodeScalar(src, dst); err != nil {
+			return &ParseStrError{key, err}
+		}
+		return nil
+	}
+}
+
+// decodeList decodes the elements of arr into the slice or the array dst,
+// placing them at their keys.
+func (r *Runtime) decodeList(key string, arr *Array, dst reflect.Value) error {
+	limit := dst.Len()
+	if dst.Kind() == reflect.Slice {
+		limit = r.ini().MaxInputVars
+	}
+
+	length := 0
+	for _, k := range arr.Keys() {
+		i, ok := k.(int)
+		if !ok || i < 0 || i >= limit {
+			return &ParseStrError{subKey(key, k), &ValueError{Message: fmt.Sprintf("key must be an integer between 0 and %d", limit-1)}}
+		}
+		if i >= length {
+			length = i + 1
+		}
+	}
+
+	if dst.Kind() == reflect.Slice {
+		dst.Set(reflect.MakeSlice(dst.Type(), length, length))
+	}
+	var err error
+	arr.Range(func(k, value any) bool {
+		err = r.decodeVariable(subKey(key, k), value, dst.Index(k.(int)))
+		return err == nil
+	})
+	return err
+}
+
+// decodeScalar converts src, which is either string or int, into the string,
+// the integer, the float or the bool dst.
+func (r *Runtime) decodeScalar(src any, dst reflect.Value) error {
+	var expected string
+	switch dst.Kind() {
+	case reflect.String:
+		expected = "string"
+	case reflect.Bool:
+		expected = "bool"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		expected = "int"
+	case reflect.Float32, reflect.Float64:
+		expected = "float"
+	default:
+		return &UnsupportedTypeError{dst.Interface()}
+	}
+	if _, ok := src.(*Array); ok {
+		return newDecodeTypeError(expected, src)
+	}
+
+	switch dst.Kind() {
+	case reflect.String:
+		str, err := r.ConvertToString(src)
+		if err != nil {
+			return err
+		}
+		dst.SetString(str)
+	case reflect.Bool:
+		dst.SetBool(r.ConvertToBool(src))
+	case reflect.Float32, reflect.Float64:
+		d, ok := r.parseArgDouble(src)
+		if !ok {
+			return newDecodeTypeError(expected, src)
+		}
+		if dst.OverflowFloat(d) {
+			return &ValueError{Message: fmt.Sprintf("%s is out of range of %s", r.floatToString(d), dst.Type())}
+		}
+		dst.SetFloat(d)
+	default:
+		l, ok := r.parseArgLong(src)
+		if !ok {
+			return newDecodeTypeError(expected, src)
+		}
+		if dst.CanInt() && !dst.OverflowInt(l) {
+			dst.SetInt(l)
+		} else if dst.CanUint() && l >= 0 && !dst.OverflowUint(uint64(l)) {
+			dst.SetUint(uint64(l))
+		} else {
+			return &ValueError{Message: fmt.Sprintf("%d is out of range of %s", l, dst.Type())}
+		}
+	}
+	return nil
+}
+
+// decodeTypeError returns *ParseStrError for src which is not of the expected
+// PHP type.
+func decodeTypeError(key, expected string, src any) error {
+	return &ParseStrError{key, newDecodeTypeError(expected, src)}
+}
+
+// newDecodeTypeError returns *TypeError for src which is not of the expected
+// PHP type, whose message is like PHP 8's TypeError for arguments.
+func newDecodeTypeError(expected string, src any) *TypeError {
+	given := zendZvalTypeName(src)
+	return &TypeError{Expected: expected, Given: given, Message: fmt.Sprintf("must be of type %s, %s given", expected, given)}
+}
+
+// subKey returns the PHP key of the element k of the array whose key is key,
+// such as "items[0]".
+func subKey(key string, k any) string {
+	if key == "" {
+		return fmt.Sprint(k)
+	}
+	return fmt.Sprintf("%s[%v]", key, k)
+}
@@ -0,0 +1,166 @@
+package gophplib
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"reflect"
+	"testing"
+)
+
+type orderItem struct {
+	Name     string  `php:"name"`
+	Quantity int     `php:"qty"`
+	Price    float64 `php:"price"`
+}
+
+type order struct {
+	ID       string            `php:"order_id"`
+	Paid     bool              `php:"paid"`
+	Items    []orderItem       `php:"items"`
+	Tags     []string          `php:"tags"`
+	Meta     map[string]string `php:"meta"`
+	Note     *string           `php:"note"`
+	Internal string            `php:"-"`
+	Amount   uint32
+}
+
+func ExampleParseStrInto() {
+	var o order
+	err := ParseStrInto("order_id=A-1&paid=1&items[0][name]=Tea&items[0][qty]=2&items[1][name]=Cake&tags[]=gift&tags[]=rush&Amount=12000", &o)
+	fmt.Println(err)
+	fmt.Printf("%s %v %v %v %d\n", o.ID, o.Paid, o.Items, o.Tags, o.Amount)
+
+	err = ParseStrInto("items[1][qty]=two", &o)
+	fmt.Println(err)
+	// Output:
+	// <nil>
+	// A-1 true [{Tea 2 0} {Cake 0 0}] [gift rush] 12000
+	// items[1][qty]: must be of type int, string given
+}
+
+func TestParseStrInto(t *testing.T) {
+	note := "hi there"
+	testCases := []struct {
+		input    string
+		expected order
+	}{
+		{"", order{}},
+		{"unknown=1&order_id[]=x&order_id=y&Internal=z", order{ID: "y"}},
+		{"paid=&Amount=0x1A", order{Amount: 26}},
+		{"paid=0", order{}},
+		{"paid=false&Amount=1e3", order{Paid: true, Amount: 1000}},
+		{"items[2][price]=1.5&items[0][qty]= 3", order{Items: []orderItem{{Quantity: 3}, {}, {Price: 1.5}}}},
+		{"tags[1]=b&tags[0]=a&tags[]=c", order{Tags: []string{"a", "b", "c"}}},
+		{"meta[a]=1&meta[2]=b&meta[]=c", order{Meta: map[string]string{"a": "1", "2": "b", "3": "c"}}},
+		{"note=hi+there", order{Note: &note}},
+	}
+	for _, tc := range testCases {
+		var o order
+		if err := ParseStrInto(tc.input, &o); err != nil {
+			t.Errorf("ParseStrInto(%q) returned error %v", tc.input, err)
+			continue
+		}
+		if !reflect.DeepEqual(o, tc.expected) {
+			t.Errorf("ParseStrInto(%q) = %+v; want %+v", tc.input, o, tc.expected)
+		}
+	}
+}
+
+func TestParseStrIntoTypes(t *testing.T) {
+	var v struct {
+		Any     any
+		Bytes   []byte
+		Array   [2]int8
+		IntKeys map[int]bool
+		Addr    netip.Addr
+		Ptr     **float32
+		Nested  map[string][]uint
+	}
+	err := ParseStrInto("Any[x]=1&Bytes=%00%FF&Array[1]=-128&IntKeys[5]=on&IntKeys[-1]=0&Addr=127.0.0.1&Ptr=0.5&Nested[a][1]=7", &v)
+	if err != nil {
+		t.Fatalf("ParseStrInto returned error %v", err)
+	}
+	if arr, ok := v.Any.(*Array); !ok || !Identical(arr, arrayOf("x", "1")) {
+		t.Errorf("Any = %#v", v.Any)
+	}
+	if string(v.Bytes) != "\x00\xff" {
+		t.Errorf("Bytes = %q", v.Bytes)
+	}
+	if v.Array != [2]int8{0, -128} {
+		t.Errorf("Array = %v", v.Array)
+	}
+	if !reflect.DeepEqual(v.IntKeys, map[int]bool{5: true, -1: false}) {
+		t.Errorf("IntKeys = %v", v.IntKeys)
+	}
+	if v.Addr != netip.MustParseAddr("127.0.0.1") {
+		t.Errorf("Addr = %v", v.Addr)
+	}
+	if v.Ptr == nil || *v.Ptr == nil || **v.Ptr != 0.5 {
+		t.Errorf("Ptr = %v", v.Ptr)
+	}
+	if !reflect.DeepEqual(v.Nested, map[string][]uint{"a": {0, 7}}) {
+		t.Errorf("Nested = %v", v.Nested)
+	}
+}
+
+func TestParseStrIntoError(t *testing.T) {
+	testCases := []struct {
+		input  string
+		dst    any
+		err    string
+		target error
+	}{
+		{"order_id[a]=1&order_id=2", &order{}, "", nil},
+		{"items=1", &order{}, "items: must be of type array, string given", ErrTypeError},
+		{"items[0]=1", &order{}, "items[0]: must be of type array, string given", ErrTypeError},
+		{"items[0][qty]=abc", &order{}, "items[0][qty]: must be of type int, string given", ErrTypeError},
+		{"items[0][qty][]=1", &order{}, "items[0][qty]: must be of type int, array given", ErrTypeError},
+		{"items[0][price]=x1.5", &order{}, "items[0][price]: must be of type float, string given", ErrTypeError},
+		{"items[x][qty]=1", &order{}, "items[x]: key must be an integer between 0 and 999", ErrValueError},
+		{"tags[-1]=a", &order{}, "tags[-1]: key must be an integer between 0 and 999", ErrValueError},
+		{"tags[1000]=a", &order{}, "tags[1000]: key must be an integer between 0 and 999", ErrValueError},
+		{"Amount=-1", &order{}, "Amount: -1 is out of range of uint32", ErrValueError},
+		{"Amount=4294967296", &order{}, "Amount: 4294967296 is out of range of uint32", ErrValueError},
+		{"a=1", &[1]int{}, "a: key must be an integer between 0 and 0", ErrValueError},
+		{"a=1", new(string), "must be of type string, array given", ErrTypeError},
+		{"a=1", order{}, "ParseStrInto: dst must be a non-nil pointer, gophplib.order given", ErrTypeError},
+		{"a=1", (*order)(nil), "ParseStrInto: dst must be a non-nil pointer, *gophplib.order given", ErrTypeError},
+	}
+	for _, tc := range testCases {
+		err := ParseStrInto(tc.input, tc.dst)
+		if tc.err == "" {
+			if err != nil {
+				t.Errorf("ParseStrInto(%q) returned error %v", tc.input, err)
+			}
+			continue
+		}
+		if err == nil || err.Error() != tc.err {
+			t.Errorf("ParseStrInto(%q) returned error %v; want %q", tc.input, err, tc.err)
+			continue
+		}
+		if tc.target != nil {
+			var perr *ParseStrError
+			if !errors.As(err, &perr) || !errors.Is(err, tc.target) {
+				t.Errorf("ParseStrInto(%q) returned error %#v; want *ParseStrError matching %v", tc.input, err, tc.target)
+			}
+		}
+	}
+}
+
+func TestParseStrIntoRuntime(t *testing.T) {
+	var diags []Diagnostic
+	r := NewRuntime(PHP80).WithDiagnosticHandler(func(d Diagnostic) { diags = append(diags, d) })
+	r.INI.MaxInputVars = 10
+
+	var o order
+	if err := r.ParseStrInto("items[0][qty]=12abc", &o); err != nil || o.Items[0].Quantity != 12 {
+		t.Errorf("got (%+v, %v)", o, err)
+	}
+	if len(diags) != 1 || diags[0].Message != "A non-numeric value encountered" {
+		t.Errorf("emitted %v", diags)
+	}
+	if err := r.ParseStrInto("tags[10]=a", &o); err == nil || err.Error() != "tags[10]: key must be an integer between 0 and 9" {
+		t.Errorf("got error %v", err)
+	}
+}
@@ -2,7 +2,7 @@ package gophplib
 
 import (
 	"fmt"
-	"reflect"
+	"net/http/httptest"
 	"strings"
 	"testing"
 
@@ -523,21 +523,217 @@ func TestParseStr(t *testing.T) {
 			input:    "foo[ 3=v",
 			expected: omap("foo_ 3", "v"),
 		},
+		{
+			name:     "EUCKR",
+			input:    "name=%B0%A1%B3%AA&%C5%B0=v",
+			expected: omap("name", "\xb0\xa1\xb3\xaa", "\xc5\xb0", "v"),
+		},
+		{
+			name:     "UnclosedBracketAtEnd",
+			input:    "a[=1&b[ =2&c[",
+			expected: omap("a_", "1", "b_ ", "2", "c_", ""),
+		},
+		{
+			name:     "UnclosedNestedBracketAtEnd",
+			input:    "a[b][=1&c[d][ =2&e[][",
+			expected: omap("a", omap("b", "1"), "c", omap("d", "2"), "e", omap(0, "")),
+		},
+		{
+			name:     "NULInKey",
+			input:    "a%00b=1&c[%00x]=2&d=%00",
+			expected: omap("a", "1", "c_", "2", "d", "\x00"),
+		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			result := dumpOrderedMap(ParseStr(tc.input))
-			expected := dumpOrderedMap(tc.expected)
-			if !reflect.DeepEqual(result, expected) {
+			result := ParseStr(tc.input)
+			if !Identical(result, tc.expected) {
 				t.Errorf(`
-expected  %#v
-actual    %#v`, tc.expected, result)
+expected  %s
+actual    %s`, dumpOrderedMap(tc.expected), dumpOrderedMap(result))
+			}
+		})
+	}
+}
+
+func TestParseStrWithOptions(t *testing.T) {
+	input := "name=%B0%A1%B3%AA&arr[%B0%A1]=%F0%9F%8D%8E"
+
+	result := ParseStrWithOptions(input, ParseStrOptions{})
+	expected := ParseStrArray(input)
+	if !Identical(result, expected) {
+		t.Errorf("got %s; want %s", dumpOrderedMap(result.ToOrderedMap()), dumpOrderedMap(expected.ToOrderedMap()))
+	}
+
+	result = ParseStrWithOptions(input, ParseStrOptions{ValidUTF8: true})
+	arr := NewArray()
+	arr.Set("\uFFFD", "🍎")
+	expected = NewArray()
+	expected.Set("name", "\uFFFD")
+	expected.Set("arr", arr)
+	if !Identical(result, expected) {
+		t.Errorf("got %s; want %s", dumpOrderedMap(result.ToOrderedMap()), dumpOrderedMap(expected.ToOrderedMap()))
+	}
+}
+
+func ExampleParseStrWithOptions_cookie() {
+	header := "sid=a+b%2Fc; theme=dark;  sid=other;=x; lang=ko"
+	fmt.Println(dumpOrderedMap(ParseStrWithOptions(header, ParseStrOptions{Cookie: true}).ToOrderedMap()))
+	fmt.Println(dumpOrderedMap(NewRuntime(PHP80).ParseStrWithOptions(header, ParseStrOptions{Cookie: true}).ToOrderedMap()))
+	// Output:
+	// omap[sid:a b/c theme:dark lang:ko]
+	// omap[sid:a+b/c theme:dark lang:ko]
+}
+
+func TestParseStrSeparators(t *testing.T) {
+	testCases := []struct {
+		options  ParseStrOptions
+		ini      string
+		input    string
+		expected *Array
+	}{
+		{ParseStrOptions{}, "", "a=1;b=2&c=3", arrayOf("a", "1;b=2", "c", "3")},
+		{ParseStrOptions{Separators: "&;"}, "", "a=1;b=2&&;c=3", arrayOf("a", "1", "b", "2", "c", "3")},
+		{ParseStrOptions{Separators: ";"}, "", "a=1&b=2", arrayOf("a", "1&b=2")},
+		{ParseStrOptions{}, ";", "a=1;b=2&c=3", arrayOf("a", "1", "b", "2&c=3")},
+		{ParseStrOptions{Separators: "&"}, ";", "a=1;b=2&c=3", arrayOf("a", "1;b=2", "c", "3")},
+		{ParseStrOptions{Separators: "\xff"}, "", "a=1\xffb=2", arrayOf("a", "1", "b", "2")},
+		{ParseStrOptions{Cookie: true, Separators: "&"}, "", "a=1&b=2; c=3", arrayOf("a", "1&b=2", "c", "3")},
+		{ParseStrOptions{Cookie: true}, "", " \t a=1;;b;\v=2; =3", arrayOf("a", "1", "b", "")},
+		{ParseStrOptions{Cookie: true}, "", "a%5B%5D=1; a+b=+%20", arrayOf("a%5B%5D", "1", "a+b", "+ ")},
+		{ParseStrOptions{Cookie: true}, "", "a[x]=1; a[x]=2; a=3; b=1; b[]=2", arrayOf("a", arrayOf("x", "2"), "b", arrayOf(0, "2"))},
+		{ParseStrOptions{Cookie: true}, "", "1=a; 1=b", arrayOf(1, "a")},
+		{ParseStrOptions{Cookie: true, ValidUTF8: true}, "", "\xb0=%B0", arrayOf("\uFFFD", "\uFFFD")},
+	}
+	for _, tc := range testCases {
+		r := NewRuntime(PHP80)
+		if tc.ini != "" {
+			r.INI.ArgSeparatorInput = tc.ini
+		}
+		result := r.ParseStrWithOptions(tc.input, tc.options)
+		if !Identical(result, tc.expected) {
+			t.Errorf("ParseStrWithOptions(%q, %+v) = %s; want %s", tc.input, tc.options, dumpOrderedMap(result.ToOrderedMap()), dumpOrderedMap(tc.expected.ToOrderedMap()))
+		}
+	}
+}
+
+func TestParseStrCookieVersion(t *testing.T) {
+	testCases := []struct {
+		version  Version
+		input    string
+		expected *Array
+	}{
+		{PHP56, "c=a+b%20d", arrayOf("c", "a b d")},
+		{PHP74, "c=a+b%20d", arrayOf("c", "a b d")},
+		{PHP80, "c=a+b%20d", arrayOf("c", "a+b d")},
+		{PHP56, "a%5B%5D=1; a+b=2; a%5B%5D=3", arrayOf("a", arrayOf(0, "1", 1, "3"), "a_b", "2")},
+		{PHP80, "a%5B%5D=1; a+b=2; a%5B%5D=3", arrayOf("a%5B%5D", "1", "a+b", "2")},
+	}
+	for _, tc := range testCases {
+		result := NewRuntime(tc.version).ParseStrWithOptions(tc.input, ParseStrOptions{Cookie: true})
+		if !Identical(result, tc.expected) {
+			t.Errorf("ParseStrWithOptions(%q) on %d = %s; want %s", tc.input, tc.version, dumpOrderedMap(result.ToOrderedMap()), dumpOrderedMap(tc.expected.ToOrderedMap()))
+		}
+	}
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Cookie", "c=a+b%20d")
+	if sg, _ := ParseRequest(req); !Identical(sg.Cookie, arrayOf("c", "a b d")) {
+		t.Errorf("ParseRequest on PHP 5.6 = %s", dumpOrderedMap(sg.Cookie.ToOrderedMap()))
+	}
+	if sg, _ := NewRuntime(PHP80).ParseRequest(req); !Identical(sg.Cookie, arrayOf("c", "a+b d")) {
+		t.Errorf("ParseRequest on PHP 8 = %s", dumpOrderedMap(sg.Cookie.ToOrderedMap()))
+	}
+}
+
+func TestParseStrCookieLimits(t *testing.T) {
+	var diags []Diagnostic
+	r := NewRuntime(PHP80).WithDiagnosticHandler(func(d Diagnostic) { diags = append(diags, d) })
+	r.INI.MaxInputVars = 2
+
+	result := r.ParseStrWithOptions("=1; ; a=1; b=2; c=3", ParseStrOptions{Cookie: true})
+	if expected := arrayOf("a", "1", "b", "2"); !Identical(result, expected) {
+		t.Errorf("got %s; want %s", dumpOrderedMap(result.ToOrderedMap()), dumpOrderedMap(expected.ToOrderedMap()))
+	}
+	if len(diags) != 1 {
+		t.Errorf("emitted %v; want a warning", diags)
+	}
+}
+
+// arrayOf returns an Array with the given keys and values, which are given
+// in turn.
+func arrayOf(pairs ...any) *Array {
+	arr := NewArray()
+	for i := 0; i < len(pairs); i += 2 {
+		arr.Set(pairs[i], pairs[i+1])
+	}
+	return arr
+}
+
+func TestParseStrLimits(t *testing.T) {
+	testCases := []struct {
+		name     string
+		ini      INI
+		input    string
+		expected string
+		warning  string
+	}{
+		{"vars", INI{MaxInputVars: 2, MaxInputNestingLevel: 64}, "a=1&&b=2&c=3&d=4", "a=1&b=2",
+			"parse_str(): Input variables exceeded 2. To increase the limit change max_input_vars in php.ini."},
+		{"vars with empty names", INI{MaxInputVars: 2, MaxInputNestingLevel: 64}, "=1&=2&a=3", "",
+			"parse_str(): Input variables exceeded 2. To increase the limit change max_input_vars in php.ini."},
+		{"vars at limit", INI{MaxInputVars: 2, MaxInputNestingLevel: 64}, "a=1&b=2&&", "a=1&b=2", ""},
+		{"nesting", INI{MaxInputVars: 1000, MaxInputNestingLevel: 2}, "a[x]=1&b=2&a[y][z]=3&a[][][]=4&a[w]=5", "b=2&a[w]=5",
+			"parse_str(): Input variable nesting level exceeded 2. To increase the limit change max_input_nesting_level in php.ini."},
+		{"nesting with numeric name", INI{MaxInputVars: 1000, MaxInputNestingLevel: 1}, "1=a&1[][]=b&2=c", "2=c",
+			"parse_str(): Input variable nesting level exceeded 1. To increase the limit change max_input_nesting_level in php.ini."},
+		{"negative nesting", INI{MaxInputVars: 1000, MaxInputNestingLevel: -1}, "a=1&a[]=2&b=3", "b=3",
+			"parse_str(): Input variable nesting level exceeded -1. To increase the limit change max_input_nesting_level in php.ini."},
+		{"negative vars", INI{MaxInputVars: -1}, "a=1&b=2", "",
+			"parse_str(): Input variables exceeded -1. To increase the limit change max_input_vars in php.ini."},
+		{"nesting at limit", INI{MaxInputVars: 1000, MaxInputNestingLevel: 2}, "a[][]=1", "a[][]=1", ""},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var diags []Diagnostic
+			r := NewRuntime(PHP80).WithDiagnosticHandler(func(d Diagnostic) { diags = append(diags, d) })
+			r.INI = tc.ini
+
+			result := r.ParseStrArray(tc.input)
+			expected := ParseStrArray(tc.expected)
+			if !Identical(result, expected) {
+				t.Errorf("got %s; want %s", dumpOrderedMap(result.ToOrderedMap()), dumpOrderedMap(expected.ToOrderedMap()))
+			}
+
+			switch {
+			case tc.warning == "" && len(diags) != 0:
+				t.Errorf("emitted %v; want nothing", diags)
+			case tc.warning != "" && (len(diags) != 1 || diags[0].Level != EWarning || diags[0].Message != tc.warning):
+				t.Errorf("emitted %v; want warning %q", diags, tc.warning)
 			}
 		})
 	}
 }
 
+func TestParseStrDefaultLimits(t *testing.T) {
+	var b strings.Builder
+	for i := 0; i < 1001; i++ {
+		fmt.Fprintf(&b, "v%d=%d&", i, i)
+	}
+	if n := ParseStrArray(b.String()).Len(); n != 1000 {
+		t.Errorf("got %d variables; want 1000", n)
+	}
+
+	key := "a" + strings.Repeat("[]", 64)
+	if n := ParseStrArray(key + "=1").Len(); n != 1 {
+		t.Errorf("got %d variables for 64 levels; want 1", n)
+	}
+	if n := ParseStrArray(key + "[]=1").Len(); n != 0 {
+		t.Errorf("got %d variables for 65 levels; want 0", n)
+	}
+}
+
 // Microbenchmark for ParseStr. Command:
 //
 //	go test -run '^$' -bench '^BenchmarkParseStr$' -benchmem \
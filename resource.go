@@ -0,0 +1,195 @@
+package gophplib
+
+import (
+	"database/sql"
+	"fmt"
+	"net"
+	"os"
+	"reflect"
+	"sync"
+)
+
+// resourceTypes maps the Go types which are treated as PHP's resources to the
+// names of their resource types, which are returned by get_resource_type.
+//   - *os.File and *net.Conn are "stream", like the resources returned by
+//     fopen and fsockopen.
+//   - *sql.DB is "mysql link", like the resources returned by mysql_connect.
+var (
+	resourceTypesMu sync.RWMutex
+	resourceTypes   = map[reflect.Type]string{
+		reflect.TypeOf((*os.File)(nil)):  "stream",
+		reflect.TypeOf((*net.Conn)(nil)): "stream",
+		reflect.TypeOf((*sql.DB)(nil)):   "mysql link",
+	}
+)
+
+// RegisterResourceType registers values of the given Go type as PHP's
+// resources of the given resource type. After registration, they are
+// converted and compared like resources, and GetResourceType returns name for
+// them. For example, *http.Client can be registered as "curl":
+//
+//	gophplib.RegisterResourceType(reflect.TypeOf((*http.Client)(nil)), "curl")
+//
+// typ must be comparable, since values are identified with ==. Pointer types
+// are recommended. Registering a type which is already registered replaces its
+// name. RegisterResourceType panics if typ is not comparable.
+func RegisterResourceType(typ reflect.Type, name string) {
+	if !typ.Comparable() {
+		panic(fmt.Sprintf("gophplib: resource type %s is not comparable", typ))
+	}
+	resourceTypesMu.Lock()
+	defer resourceTypesMu.Unlock()
+	resourceTypes[typ] = name
+}
+
+// resourceTypeOf returns the name of the resource type of the given value. It
+// returns false if the value is not a resource.
+func resourceTypeOf(value any) (string, bool) {
+	if value == nil {
+		return "", false
+	}
+	resourceTypesMu.RLock()
+	defer resourceTypesMu.RUnlock()
+	name, ok := resourceTypes[reflect.TypeOf(value)]
+	return name, ok
+}
+
+// ResourceRegistry assigns resource IDs to Go values which are treated as
+// PHP's resources, like PHP's EG(regular_list). Each value is given the next
+// sequential ID, starting from 1, when it is seen for the first time, and
+// keeps the ID until it is released. IDs are never reused, just like PHP.
+//
+// Note that PHP's CLI opens STDIN, STDOUT and STDERR as resources 1, 2 and 3
+// before running a script, so IDs of PHP's resources usually start from a
+// larger number.
+//
+// ResourceRegistry holds references to the values it has seen, so that their
+// IDs are stable. Call Release when a value is closed, or use a separate
+// ResourceRegistry for each group of values, such as each HTTP request, to
+// let them be garbage collected.
+//
+// A ResourceRegistry is safe for concurrent use.
+type ResourceRegistry struct {
+	mu     sync.Mutex
+	nextID int
+	ids    map[any]int
+}
+
+// NewResourceRegistry returns a new ResourceRegistry, which assigns 1 to the
+// first resource.
+func NewResourceRegistry() *ResourceRegistry {
+	return &ResourceRegistry{nextID: 1, ids: make(map[any]int)}
+}
+
+// ID returns the resource ID of the given value, assigning the next ID if it
+// is seen for the first time. It returns false if the value is not a
+// resource.
+func (rr *ResourceRegistry) ID(value any) (int, bool) {
+	if _, ok := resourceTypeOf(value); !ok {
+		return 0, false
+	}
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+	if id, ok := rr.ids[value]; ok {
+		return id, true
+	}
+	id := rr.nextID
+	rr.nextID++
+	rr.ids[value] = id
+	return id, true
+}
+
+// Release forgets the given value. If it is seen again, it is given a new ID,
+// just like a resource which is opened again in PHP.
+func (rr *ResourceRegistry) Release(value any) {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+	delete(rr.ids, value)
+}
+
+// defaultResources is the ResourceRegistry used by Runtimes whose Resources is
+// nil, including the Runtime used by the package-level functions.
+var defaultResources = NewResourceRegistry()
+
+// DefaultResourceRegistry returns the ResourceRegistry shared by the
+// package-level functions and the Runtimes whose Resources is nil. It is also
+// used for the resources used as keys of Array.
+//
+// IDs of the shared registry are process-global, so they depend on every
+// resource converted by any goroutine, and the values it has seen are never
+// garbage collected until they are released. Call Release of the returned
+// registry when a value is closed, or use a Runtime with its own Resources
+// if the IDs must be reproducible, such as when they are hashed or signed.
+func DefaultResourceRegistry() *ResourceRegistry {
+	return defaultResources
+}
+
+// resources returns the ResourceRegistry of r.
+func (r *Runtime) resources() *ResourceRegistry {
+	if r.Resources == nil {
+		return defaultResources
+	}
+	return r.Resources
+}
+
+// resourceID returns the resource ID of the given value, or false if the value
+// is not a resource.
+func (r *Runtime) resourceID(value any) (int, bool) {
+	return r.resources().ID(value)
+}
+
+// GetResourceType is a ported function that works exactly the same as PHP's
+// get_resource_type function. It returns the name of the resource type of the
+// given resource, such as "stream" for *os.File. See RegisterResourceType for
+// custom resource types. For more information, see the [official PHP
+// documentation].
+//
+// If value is not a resource, it returns *TypeError, like ParseParameters.
+//
+// Reference:
+//   - https://github.com/php/php-src/blob/php-5.6.40/Zend/zend_builtin_functions.c
+//   - https://github.com/php/php-src/blob/php-8.3.0/Zend/zend_builtin_functions.c
+//
+// [official PHP documentation]: https://www.php.net/manual/en/function.get-resource-type.php
+func (r *Runtime) GetResourceType(value any) (string, error) {
+	var resource any
+	if err := r.ParseParameters(Function{"get_resource_type", []string{"resource"}}, []any{value}, "r", &resource); err != nil {
+		return "", err
+	}
+	name, _ := resourceTypeOf(resource)
+	return name, nil
+}
+
+// GetResourceType works the same as Runtime.GetResourceType of a Runtime emulating PHP 5.6.
+func GetResourceType(value any) (string, error) {
+	return defaultRuntime.GetResourceType(value)
+}
+
+// GetResourceID is a ported function that works exactly the same as PHP's
+// get_resource_id function. It returns the resource ID of the given resource,
+// which is assigned by the ResourceRegistry of the Runtime. It is the same as
+// converting the resource to int. For more information, see the [official PHP
+// documentation].
+//
+// get_resource_id was added in PHP 8.0, but this function is available for all
+// versions.
+//
+// If value is not a resource, it returns *TypeError, like ParseParameters.
+//
+// Reference:
+//   - https://github.com/php/php-src/blob/php-8.3.0/Zend/zend_builtin_functions.c
+//
+// [official PHP documentation]: https://www.php.net/manual/en/function.get-resource-id.php
+func (r *Runtime) GetResourceID(value any) (int, error) {
+	var resource any
+	if err := r.ParseParameters(Function{"get_resource_id", []string{"resource"}}, []any{value}, "r", &resource); err != nil {
+		return 0, err
+	}
+	id, _ := r.resourceID(resource)
+	return id, nil
+}
+
+// GetResourceID works the same as Runtime.GetResourceID of a Runtime emulating PHP 5.6.
+func GetResourceID(value any) (int, error) {
+	return defaultRuntime.GetResourceID(value)
+}
@@ -0,0 +1,145 @@
+package gophplib
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// curlHandle is a custom resource type used in tests.
+type curlHandle struct {
+	url string
+}
+
+func init() {
+	RegisterResourceType(reflect.TypeOf((*curlHandle)(nil)), "curl")
+}
+
+func ExampleResourceRegistry() {
+	r := NewRuntime(PHP56)
+	r.Resources = NewResourceRegistry()
+
+	file := getFile()
+	defer file.Close()
+	handle := &curlHandle{"https://example.com"}
+
+	fmt.Println(r.ConvertToString(file))
+	fmt.Println(r.ConvertToString(handle))
+	fmt.Println(r.ConvertToLong(file))
+	fmt.Println(r.GetResourceType(handle))
+	// Output:
+	// Resource id #1 <nil>
+	// Resource id #2 <nil>
+	// 1 <nil>
+	// curl <nil>
+}
+
+func TestResourceRegistry(t *testing.T) {
+	rr := NewResourceRegistry()
+	a, b := &curlHandle{"a"}, &curlHandle{"b"}
+
+	for _, tc := range []struct {
+		value    any
+		expected int
+	}{{a, 1}, {b, 2}, {a, 1}, {b, 2}} {
+		if id, ok := rr.ID(tc.value); !ok || id != tc.expected {
+			t.Errorf("ID(%v) = (%d, %t); want (%d, true)", tc.value, id, ok, tc.expected)
+		}
+	}
+
+	rr.Release(a)
+	if id, ok := rr.ID(a); !ok || id != 3 {
+		t.Errorf("ID after Release = (%d, %t); want (3, true)", id, ok)
+	}
+
+	for _, value := range []any{nil, 1, "a", curlHandle{"a"}, []int{1}} {
+		if _, ok := rr.ID(value); ok {
+			t.Errorf("ID(%v) succeeded; want failure for non-resource", value)
+		}
+	}
+}
+
+func TestDefaultResourceRegistry(t *testing.T) {
+	rr := DefaultResourceRegistry()
+	handle := &curlHandle{"default"}
+
+	s, _ := ConvertToString(handle)
+	id, ok := rr.ID(handle)
+	if !ok || s != fmt.Sprintf("Resource id #%d", id) {
+		t.Errorf("ConvertToString = %q; want the ID %d of DefaultResourceRegistry", s, id)
+	}
+	if s, _ := (&Runtime{Version: PHP80}).ConvertToString(handle); s != fmt.Sprintf("Resource id #%d", id) {
+		t.Errorf("ConvertToString of a Runtime without Resources = %q; want the ID %d", s, id)
+	}
+
+	rr.Release(handle)
+	rr.mu.Lock()
+	_, retained := rr.ids[handle]
+	rr.mu.Unlock()
+	if retained {
+		t.Errorf("released value is still referenced")
+	}
+	if next, _ := rr.ID(handle); next <= id {
+		t.Errorf("ID after Release = %d; want a new ID greater than %d", next, id)
+	}
+	rr.Release(handle)
+}
+
+func TestResourceConversions(t *testing.T) {
+	r := NewRuntime(PHP80)
+	r.Resources = NewResourceRegistry()
+	handle := &curlHandle{"a"}
+
+	if s, err := r.ConvertToString(handle); err != nil || s != "Resource id #1" {
+		t.Errorf("ConvertToString = (%q, %v); want Resource id #1", s, err)
+	}
+	if n, err := r.Add(handle, 1); err != nil || n != int64(2) {
+		t.Errorf("Add = (%v, %v); want 2", n, err)
+	}
+	if eq, err := r.LooseEquals(handle, 1); err != nil || !eq {
+		t.Errorf("LooseEquals = (%t, %v); want true", eq, err)
+	}
+	if !Identical(handle, handle) || Identical(handle, &curlHandle{"a"}) {
+		t.Errorf("resources should be identical only to themselves")
+	}
+	if name := zendZvalTypeName(handle); name != "resource" {
+		t.Errorf("zendZvalTypeName = %q; want resource", name)
+	}
+	if d, err := r.ConvertToDouble(handle); err != nil || d != 1 {
+		t.Errorf("ConvertToDouble = (%v, %v); want 1", d, err)
+	}
+	var v any = handle
+	if err := r.Settype(&v, "float"); err != nil || v != 1.0 {
+		t.Errorf("Settype = (%v, %v); want 1.0", v, err)
+	}
+}
+
+func TestGetResourceType(t *testing.T) {
+	testCases := []struct {
+		version  Version
+		value    any
+		expected string
+		err      string
+	}{
+		{PHP56, getFile(), "stream", ""},
+		{PHP80, &curlHandle{}, "curl", ""},
+		{PHP56, "file", "", "get_resource_type() expects parameter 1 to be resource, string given"},
+		{PHP80, "file", "", "get_resource_type(): Argument #1 ($resource) must be of type resource, string given"},
+	}
+	for _, tc := range testCases {
+		name, err := NewRuntime(tc.version).GetResourceType(tc.value)
+		if tc.err != "" {
+			if err == nil || err.Error() != tc.err {
+				t.Errorf("GetResourceType(%v) on %d returned error %v; want %q", tc.value, tc.version, err, tc.err)
+			}
+			continue
+		}
+		if err != nil || name != tc.expected {
+			t.Errorf("GetResourceType(%v) on %d = (%q, %v); want %q", tc.value, tc.version, name, err, tc.expected)
+		}
+	}
+
+	if _, err := GetResourceID(1); err == nil {
+		t.Errorf("GetResourceID(1) succeeded; want error")
+	}
+}
@@ -0,0 +1,63 @@
+package gophplib
+
+// Version identifies a PHP version, in the same format as PHP's PHP_VERSION_ID
+// constant. (ex: 50640 for PHP 5.6.40, 80300 for PHP 8.3.0)
+//
+// Constants are provided for the versions whose behaviors differ from each
+// other, but any PHP_VERSION_ID can be used.
+type Version int
+
+const (
+	PHP56 Version = 50600
+	PHP70 Version = 70000
+	PHP71 Version = 70100
+	PHP73 Version = 70300
+	PHP74 Version = 70400
+	PHP80 Version = 80000
+	PHP81 Version = 80100
+	PHP83 Version = 80300
+)
+
+// Runtime represents a PHP runtime whose behavior is emulated by this package.
+// Functions whose behavior differs between PHP versions are available as
+// methods of Runtime. For example:
+//   - Numeric strings: PHP 7.1 and later parse strings such as "1e3" as
+//     numbers when converting them to int, and PHP 8 changed the comparison
+//     between numbers and non-numeric strings.
+//   - Floats: PHP 7 and later convert NaN and infinities to 0 when converting
+//     them to int.
+//   - Errors: PHP 8 throws a TypeError for arguments of invalid types, while
+//     older versions emit a warning and return NULL. Functions return an error
+//     in both cases, with the same message as the warning or the TypeError.
+//
+// Functions which are not methods of Runtime behave the same for all the
+// versions.
+//
+// The package-level functions behave the same as the methods of a Runtime
+// emulating PHP 5.6 with the default INI settings.
+type Runtime struct {
+	// Version is the PHP version to emulate.
+	Version Version
+
+	// INI is the INI settings of the runtime.
+	INI INI
+
+	// DiagnosticHandler receives notices, warnings and deprecations emitted
+	// by the functions of the runtime. They are discarded if it is nil.
+	DiagnosticHandler DiagnosticHandler
+
+	// Resources assigns resource IDs to the resources converted by the
+	// functions of the runtime. If it is nil, the ResourceRegistry shared by
+	// all the runtimes and the package-level functions is used, whose IDs are
+	// process-global. See DefaultResourceRegistry.
+	Resources *ResourceRegistry
+}
+
+// NewRuntime returns a new Runtime emulating the given PHP version, with the
+// default INI settings of the version.
+func NewRuntime(version Version) *Runtime {
+	return &Runtime{Version: version, INI: DefaultINI(version)}
+}
+
+// defaultRuntime is the Runtime used by the package-level functions.
+var defaultRuntime = NewRuntime(PHP56)
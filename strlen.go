@@ -1,20 +1,16 @@
 package gophplib
 
-import (
-	"fmt"
-	"reflect"
-)
-
-// Strlen is a ported function that works exactly the same as PHP 5.6's strlen function.
+// Strlen is a ported function that works exactly the same as PHP's strlen function.
 // In PHP 5.6, when the strlen() function is used with a data type other
 // than a string, it automatically converts the given variable into a string
 // before processing it. To achieve the same behavior in Go,
-// this function converts an argument to string using the zendParseArgAsString() function.
+// this function converts an argument to string using Runtime.ParseParameters.
 // For more information, see the [official PHP documentation].
 //
 // This function returns error if given argument is not one of following:
-// string, int, int64, float64, bool, nil, and any type which does not implement
-// interface { toString() string }.
+// string, int, int64, float64, bool, nil, and any type which implements
+// Stringable. The error message is the same as the warning emitted by PHP, or the TypeError thrown
+// by PHP 8.
 //
 // Reference :
 //   - https://github.com/php/php-src/blob/php-5.6.40/Zend/zend_builtin_functions.c#L479-L492
@@ -26,11 +22,16 @@ import (
 //   - https://github.com/php/php-src/blob/php-5.6.40/ext/standard/tests/strings/strlen_basic.phpt
 //
 // [official PHP documentation]: https://www.php.net/manual/en/function.strlen.php
-func Strlen(value any) (int, error) {
+func (r *Runtime) Strlen(value any) (int, error) {
 	// Convert a value to string
-	characterString, err := zendParseArgAsString(value)
-	if err != nil {
-		return 0, fmt.Errorf("unsupported type : %s", reflect.TypeOf(value))
+	var characterString string
+	if err := r.ParseParameters(Function{"strlen", []string{"string"}}, []any{value}, "s", &characterString); err != nil {
+		return 0, err
 	}
 	return len(characterString), nil
 }
+
+// Strlen works the same as Runtime.Strlen of a Runtime emulating PHP 5.6.
+func Strlen(value any) (int, error) {
+	return defaultRuntime.Strlen(value)
+}
@@ -97,7 +97,7 @@ func TestStrlen(t *testing.T) {
 		t.Run(testName, func(t *testing.T) {
 			result, err := Strlen(tc.any)
 			if err != nil {
-				expectedErr := fmt.Errorf("unsupported type : %s", reflect.TypeOf(tc.any))
+				expectedErr := fmt.Errorf("strlen() expects parameter 1 to be string, %s given", defaultRuntime.zvalTypeName(tc.any))
 				if err.Error() != expectedErr.Error() {
 					t.Errorf("%s: expected error : %s, got %s", testName, expectedErr, err)
 				}
@@ -115,7 +115,15 @@ func TestStrlenError(t *testing.T) {
 	if err == nil {
 		t.Errorf("expected error, got nil")
 	}
-	if err.Error() != "unsupported type : []int" {
-		t.Errorf("expected error : unsupported type : []int, got %s", err)
+	if err.Error() != "strlen() expects parameter 1 to be string, array given" {
+		t.Errorf("expected error : strlen() expects parameter 1 to be string, array given, got %s", err)
+	}
+}
+
+func TestStrlenErrorPHP8(t *testing.T) {
+	_, err := NewRuntime(PHP80).Strlen([]int{1, 2, 3})
+	expected := "strlen(): Argument #1 ($string) must be of type string, array given"
+	if err == nil || err.Error() != expected {
+		t.Errorf("expected error : %s, got %v", expected, err)
 	}
 }
@@ -0,0 +1,349 @@
+package gophplib
+
+import (
+	"bytes"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strings"
+)
+
+// Superglobals holds the superglobal arrays which PHP builds from an HTTP
+// request, before running the script.
+type Superglobals struct {
+	// Get is $_GET, which is parsed from the query string.
+	Get *Array
+	// Post is $_POST, which is parsed from the body of POST requests whose
+	// content type is either application/x-www-form-urlencoded or
+	// multipart/form-data.
+	Post *Array
+	// Cookie is $_COOKIE, which is parsed from the Cookie headers.
+	Cookie *Array
+	// Request is $_REQUEST, which is the merge of Get, Post and Cookie in the
+	// order of the RequestOrder INI setting.
+	Request *Array
+	// Input is the content of php://input, which is the raw body of the
+	// request. It is nil for multipart/form-data POST requests, since PHP
+	// consumes their bodies to build $_POST and $_FILES.
+	Input []byte
+}
+
+// ParseRequest builds the superglobals from the given request exactly like
+// PHP does, within the limits of the INI settings of the Runtime.
+//   - $_GET is parsed from the raw query string like ParseStr, using the
+//     ArgSeparatorInput INI setting as the separators.
+//   - $_COOKIE is parsed from the Cookie headers like ParseStrWithOptions with
+//     the Cookie option. Multiple Cookie headers are joined with "; ", like
+//     web servers do for HTTP/2.
+//   - $_POST is parsed only if the method is POST. The body of
+//     application/x-www-form-urlencoded is split on '&' regardless of the
+//     ArgSeparatorInput INI setting, and the variable which exceeds
+//     MaxInputVars is still registered before the warning, just like PHP.
+//     The fields of multipart/form-data are registered without being
+//     decoded, and the files are skipped since $_FILES is not supported.
+//   - $_REQUEST is merged recursively, so "a[x]=1" in the query string and
+//     "a[y]=2" in the body make an array with both keys.
+//
+// Only 'G', 'P' and 'C' of the VariablesOrder and RequestOrder INI settings
+// are used, since $_ENV and $_SERVER are not supported. The superglobals not
+// in VariablesOrder are left empty, like PHP.
+//
+// The body is read entirely, and replaced with a new reader of the same
+// content so that it can be read again. Limit its size with
+// http.MaxBytesReader, since post_max_size is not supported. It returns error
+// only if the body can not be read. Diagnostics like "Unknown: Input variables
+// exceeded 1000. To increase the limit change max_input_vars in php.ini." are
+// emitted to the diagnostic handler of the Runtime.
+//
+// References:
+//   - https://github.com/php/php-src/blob/php-8.3.0/main/php_variables.c
+//   - https://github.com/php/php-src/blob/php-8.3.0/main/rfc1867.c
+//   - https://github.com/php/php-src/blob/php-8.3.0/main/SAPI.c
+func (r *Runtime) ParseRequest(req *http.Request) (*Superglobals, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		if body, err = io.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	sg := &Superglobals{Get: NewArray(), Post: NewArray(), Cookie: NewArray(), Request: NewArray(), Input: body}
+	ini := r.ini()
+	variablesOrder := strings.ToUpper(ini.VariablesOrder)
+
+	if strings.Contains(variablesOrder, "G") {
+		sg.Get = r.treatData(req.URL.RawQuery, ParseStrOptions{}, "Unknown")
+	}
+
+	if req.Method == http.MethodPost {
+		// Only the part before ';', ',' or ' ' is compared, case-insensitively
+		contentType := req.Header.Get("Content-Type")
+		mediaType := strings.ToLower(contentType)
+		if i := strings.IndexAny(mediaType, "; ,"); i != -1 {
+			mediaType = mediaType[:i]
+		}
+
+		switch mediaType {
+		case "application/x-www-form-urlencoded":
+			if strings.Contains(variablesOrder, "P") {
+				sg.Post = r.parsePostVars(string(body))
+			}
+		case "multipart/form-data":
+			sg.Input = nil
+			post := r.parseMultipart(contentType, body)
+			if strings.Contains(variablesOrder, "P") {
+				sg.Post = post
+			}
+		}
+	}
+
+	if strings.Contains(variablesOrder, "C") {
+		cookie := strings.Join(req.Header.Values("Cookie"), "; ")
+		sg.Cookie = r.treatData(cookie, ParseStrOptions{Cookie: true}, "Unknown")
+	}
+
+	requestOrder := ini.RequestOrder
+	if requestOrder == "" {
+		requestOrder = ini.VariablesOrder
+	}
+	merged := map[rune]bool{}
+	for _, c := range strings.ToUpper(requestOrder) {
+		if merged[c] {
+			continue
+		}
+		switch c {
+		case 'G':
+			autoglobalMerge(sg.Request, sg.Get)
+		case 'P':
+			autoglobalMerge(sg.Request, sg.Post)
+		case 'C':
+			autoglobalMerge(sg.Request, sg.Cookie)
+		default:
+			continue
+		}
+		merged[c] = true
+	}
+
+	return sg, nil
+}
+
+// ParseRequest works the same as Runtime.ParseRequest of a Runtime emulating PHP 5.6.
+func ParseRequest(req *http.Request) (*Superglobals, error) {
+	return defaultRuntime.ParseRequest(req)
+}
+
+// parsePostVars is a ported function that works exactly the same as PHP's
+// add_post_vars function, which parses the body of
+// application/x-www-form-urlencoded for $_POST.
+func (r *Runtime) parsePostVars(body string) *Array {
+	ret := NewArray()
+	maxVars := r.ini().MaxInputVars
+	count := 0
+	for body != "" {
+		var pair string
+		pair, body, _ = strings.Cut(body, "&")
+
+		// Empty pairs are registered as variables without names, which are
+		// ignored but counted.
+		key, value, _ := strings.Cut(pair, "=")
+		r.registerVariableSafe(Urldecode(key), Urldecode(value), ret, false, "Unknown")
+
+		count++
+		if count > maxVars {
+			r.emit(EWarning, "Unknown: Input variables exceeded %d. To increase the limit change max_input_vars in php.ini.", maxVars)
+			break
+		}
+	}
+	return ret
+}
+
+// parseMultipart parses the fields of multipart/form-data for $_POST like
+// PHP's rfc1867_post_handler function. Files are skipped, and the parsing
+// stops at the first malformed part, keeping the fields parsed so far.
+func (r *Runtime) parseMultipart(contentType string, body []byte) *Array {
+	ret := NewArray()
+
+	boundary, warning := multipartBoundary(contentType)
+	if warning != "" {
+		r.emit(EWarning, "Unknown: %s in multipart/form-data POST data", warning)
+		return ret
+	}
+
+	reader := multipart.NewReader(bytes.NewReader(body), boundary)
+	maxVars := r.ini().MaxInputVars
+	count := 0
+	for {
+		// PHP does not decode Content-Transfer-Encoding
+		part, err := reader.NextRawPart()
+		if err != nil {
+			break
+		}
+
+		name, hasName, hasFilename := parseContentDisposition(part.Header.Get("Content-Disposition"))
+		if !hasName || hasFilename {
+			// $_FILES is not supported
+			continue
+		}
+
+		value, err := io.ReadAll(part)
+		if err != nil {
+			break
+		}
+
+		count++
+		if count <= maxVars {
+			r.registerVariableSafe(name, string(value), ret, false, "Unknown")
+		} else if count == maxVars+1 {
+			r.emit(EWarning, "Unknown: Input variables exceeded %d. To increase the limit change max_input_vars in php.ini.", maxVars)
+		}
+	}
+	return ret
+}
+
+// multipartBoundary extracts the boundary from the Content-Type header of
+// multipart/form-data like PHP's rfc1867_post_handler function. Unlike
+// mime.ParseMediaType, it only looks for the text "boundary" and reads the
+// value after the following '=', so it accepts headers such as
+// "multipart/form-data; boundary=a/b". It returns the reason of the warning
+// emitted by PHP if the boundary is missing or invalid.
+func multipartBoundary(contentType string) (boundary, warning string) {
+	idx := strings.Index(contentType, "boundary")
+	if idx == -1 {
+		idx = strings.Index(strings.ToLower(contentType), "boundary")
+	}
+	if idx == -1 {
+		return "", "Missing boundary"
+	}
+	eq := strings.IndexByte(contentType[idx:], '=')
+	if eq == -1 {
+		return "", "Missing boundary"
+	}
+
+	boundary = contentType[idx+eq+1:]
+	if strings.HasPrefix(boundary, `"`) {
+		boundary = boundary[1:]
+		end := strings.IndexByte(boundary, '"')
+		if end == -1 {
+			return "", "Invalid boundary"
+		}
+		return boundary[:end], ""
+	}
+	if end := strings.IndexAny(boundary, ",;"); end != -1 {
+		boundary = boundary[:end]
+	}
+	return boundary, ""
+}
+
+// parseContentDisposition parses the Content-Disposition header of a part of
+// multipart/form-data like PHP's rfc1867_post_handler function, and returns
+// the name parameter. Unlike mime.ParseMediaType, unquoted values may contain
+// any characters except whitespaces, such as "name=a[b]", and RFC 2231
+// parameters such as "name*=" are not decoded.
+func parseContentDisposition(header string) (name string, hasName, hasFilename bool) {
+	cd := strings.TrimLeftFunc(header, isASCIISpace)
+	for cd != "" {
+		var pair string
+		pair, cd = apGetword(cd, ';')
+		cd = strings.TrimLeftFunc(cd, isASCIISpace)
+		if !strings.Contains(pair, "=") {
+			continue
+		}
+		key, value := apGetword(pair, '=')
+		switch {
+		case strings.EqualFold(key, "name"):
+			name, hasName = apGetwordConf(value), true
+		case strings.EqualFold(key, "filename"):
+			hasFilename = true
+		}
+	}
+	return name, hasName, hasFilename
+}
+
+// apGetword is a ported function that works exactly the same as PHP's
+// php_ap_getword function. It returns the word before stop, skipping quoted
+// strings, and the rest of line after the consecutive stops.
+func apGetword(line string, stop byte) (word, rest string) {
+	pos := 0
+	for pos < len(line) && line[pos] != stop {
+		if quote := line[pos]; quote == '"' || quote == '\'' {
+			pos++
+			for pos < len(line) && line[pos] != quote {
+				if line[pos] == '\\' && pos+1 < len(line) && line[pos+1] == quote {
+					pos += 2
+				} else {
+					pos++
+				}
+			}
+			if pos < len(line) {
+				pos++
+			}
+		} else {
+			pos++
+		}
+	}
+	if pos >= len(line) {
+		return line, ""
+	}
+	word = line[:pos]
+	for pos < len(line) && line[pos] == stop {
+		pos++
+	}
+	return word, line[pos:]
+}
+
+// apGetwordConf is a ported function that works exactly the same as PHP's
+// php_ap_getword_conf function. It returns the value of a parameter, which is
+// either quoted or ends at a whitespace, unescaping backslashes like PHP's
+// substring_conf function.
+func apGetwordConf(str string) string {
+	str = strings.TrimLeftFunc(str, isASCIISpace)
+	if str == "" {
+		return ""
+	}
+
+	var quote byte
+	if str[0] == '"' || str[0] == '\'' {
+		quote = str[0]
+		str = str[1:]
+	} else if end := strings.IndexFunc(str, isASCIISpace); end != -1 {
+		str = str[:end]
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(str) && (quote == 0 || str[i] != quote); i++ {
+		if str[i] == '\\' && i+1 < len(str) && (str[i+1] == '\\' || (quote != 0 && str[i+1] == quote)) {
+			i++
+		}
+		b.WriteByte(str[i])
+	}
+	return b.String()
+}
+
+// isASCIISpace is isAsciiWhitespace for strings.TrimLeftFunc and alike.
+func isASCIISpace(c rune) bool {
+	return c < 0x80 && isAsciiWhitespace(byte(c))
+}
+
+// autoglobalMerge is a ported function that works exactly the same as PHP's
+// php_autoglobal_merge function, which merges src into dest recursively for
+// $_REQUEST. Arrays of src are copied, so dest does not share them.
+func autoglobalMerge(dest, src *Array) {
+	src.Range(func(key, value any) bool {
+		srcArr, ok := value.(*Array)
+		if !ok {
+			dest.set(key, value)
+			return true
+		}
+		if destValue, ok := dest.Get(key); ok {
+			if destArr, ok := destValue.(*Array); ok {
+				autoglobalMerge(destArr, srcArr)
+				return true
+			}
+		}
+		dest.set(key, srcArr.Copy())
+		return true
+	})
+}
@@ -0,0 +1,306 @@
+package gophplib
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"os"
+	"strings"
+	"testing"
+)
+
+func ExampleParseRequest() {
+	req := httptest.NewRequest("POST", "/pay?id=1&a[x]=get", strings.NewReader("id=2&a[y]=post&memo=hi+there"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=UTF-8")
+	req.Header.Set("Cookie", "sid=abc; id=3")
+
+	sg, _ := ParseRequest(req)
+	PrintR(os.Stdout, sg.Request)
+	fmt.Println(string(sg.Input))
+	// Output:
+	// Array
+	// (
+	//     [id] => 3
+	//     [a] => Array
+	//         (
+	//             [x] => get
+	//             [y] => post
+	//         )
+	//
+	//     [memo] => hi there
+	//     [sid] => abc
+	// )
+	// id=2&a[y]=post&memo=hi+there
+}
+
+func TestParseRequest(t *testing.T) {
+	req := httptest.NewRequest("POST", "/?a=1;b=2&c+d=%2B", strings.NewReader("e=1;f=2&&g"))
+	req.Header.Set("Content-Type", "Application/X-WWW-Form-Urlencoded")
+	req.Header.Add("Cookie", "h=1+1; h=2; i[]=%41")
+	req.Header.Add("Cookie", "j=3")
+
+	r := NewRuntime(PHP80)
+	r.INI.ArgSeparatorInput = "&;"
+	sg, err := r.ParseRequest(req)
+	if err != nil {
+		t.Fatalf("ParseRequest returned error %v", err)
+	}
+
+	testCases := []struct {
+		name     string
+		result   *Array
+		expected *Array
+	}{
+		{"Get", sg.Get, arrayOf("a", "1", "b", "2", "c_d", "+")},
+		{"Post", sg.Post, arrayOf("e", "1;f=2", "g", "")},
+		{"Cookie", sg.Cookie, arrayOf("h", "1+1", "i", arrayOf(0, "A"), "j", "3")},
+		{"Request", sg.Request, arrayOf("a", "1", "b", "2", "c_d", "+", "e", "1;f=2", "g", "", "h", "1+1", "i", arrayOf(0, "A"), "j", "3")},
+	}
+	for _, tc := range testCases {
+		if !Identical(tc.result, tc.expected) {
+			t.Errorf("%s = %s; want %s", tc.name, dumpOrderedMap(tc.result.ToOrderedMap()), dumpOrderedMap(tc.expected.ToOrderedMap()))
+		}
+	}
+
+	if string(sg.Input) != "e=1;f=2&&g" {
+		t.Errorf("Input = %q", sg.Input)
+	}
+	if body, _ := io.ReadAll(req.Body); string(body) != "e=1;f=2&&g" {
+		t.Errorf("body = %q; want it to be readable again", body)
+	}
+}
+
+func TestParseRequestMethod(t *testing.T) {
+	for _, method := range []string{"GET", "PUT", "post"} {
+		req := httptest.NewRequest(method, "/", strings.NewReader("a=1"))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		sg, _ := ParseRequest(req)
+		if sg.Post.Len() != 0 || string(sg.Input) != "a=1" {
+			t.Errorf("%s: Post = %s, Input = %q; want empty Post and the body", method, dumpOrderedMap(sg.Post.ToOrderedMap()), sg.Input)
+		}
+	}
+
+	req := httptest.NewRequest("POST", "/", strings.NewReader(`{"a":1}`))
+	req.Header.Set("Content-Type", "application/json")
+	sg, _ := ParseRequest(req)
+	if sg.Post.Len() != 0 || string(sg.Input) != `{"a":1}` {
+		t.Errorf("json: Post = %s, Input = %q", dumpOrderedMap(sg.Post.ToOrderedMap()), sg.Input)
+	}
+}
+
+func TestParseRequestMultipart(t *testing.T) {
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	w.WriteField("a[]", "x+y%20")
+	w.WriteField("a[]", "z")
+	f, _ := w.CreateFormFile("upload", "test.txt")
+	f.Write([]byte("content"))
+	w.WriteField("b c", "1")
+	w.Close()
+
+	req := httptest.NewRequest("POST", "/?q=1", &body)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+
+	var diags []Diagnostic
+	r := NewRuntime(PHP80).WithDiagnosticHandler(func(d Diagnostic) { diags = append(diags, d) })
+	sg, err := r.ParseRequest(req)
+	if err != nil {
+		t.Fatalf("ParseRequest returned error %v", err)
+	}
+	if expected := arrayOf("a", arrayOf(0, "x+y%20", 1, "z"), "b_c", "1"); !Identical(sg.Post, expected) {
+		t.Errorf("Post = %s; want %s", dumpOrderedMap(sg.Post.ToOrderedMap()), dumpOrderedMap(expected.ToOrderedMap()))
+	}
+	if sg.Input != nil {
+		t.Errorf("Input = %q; want nil", sg.Input)
+	}
+	if len(diags) != 0 {
+		t.Errorf("emitted %v", diags)
+	}
+
+	req = httptest.NewRequest("POST", "/", strings.NewReader("a=1"))
+	req.Header.Set("Content-Type", "multipart/form-data")
+	sg, _ = r.ParseRequest(req)
+	if sg.Post.Len() != 0 || sg.Input != nil || len(diags) != 1 || diags[0].Message != "Unknown: Missing boundary in multipart/form-data POST data" {
+		t.Errorf("got Post %s, Input %q, diagnostics %v", dumpOrderedMap(sg.Post.ToOrderedMap()), sg.Input, diags)
+	}
+}
+
+func TestParseRequestBoundary(t *testing.T) {
+	testCases := []struct {
+		contentType string
+		boundary    string
+		warning     string
+	}{
+		{"multipart/form-data; boundary=a/b", "a/b", ""},
+		{"multipart/form-data; boundary=abc; charset", "abc", ""},
+		{"multipart/form-data; boundary=abc, foo=bar", "abc", ""},
+		{`multipart/form-data; boundary="a;b"`, "a;b", ""},
+		{"multipart/form-data; BOUNDARY=abc", "abc", ""},
+		{`multipart/form-data; boundary="abc`, "", "Unknown: Invalid boundary in multipart/form-data POST data"},
+		{"multipart/form-data; boundary", "", "Unknown: Missing boundary in multipart/form-data POST data"},
+	}
+	for _, tc := range testCases {
+		boundary := tc.boundary
+		if boundary == "" {
+			boundary = "abc"
+		}
+		body := "--" + boundary + "\r\nContent-Disposition: form-data; name=\"a\"\r\n\r\n1\r\n--" + boundary + "--\r\n"
+		req := httptest.NewRequest("POST", "/", strings.NewReader(body))
+		req.Header.Set("Content-Type", tc.contentType)
+
+		var diags []Diagnostic
+		r := NewRuntime(PHP56).WithDiagnosticHandler(func(d Diagnostic) { diags = append(diags, d) })
+		sg, _ := r.ParseRequest(req)
+		if tc.warning != "" {
+			if sg.Post.Len() != 0 || len(diags) != 1 || diags[0].Message != tc.warning {
+				t.Errorf("%s: got Post %s, diagnostics %v; want warning %q", tc.contentType, dumpOrderedMap(sg.Post.ToOrderedMap()), diags, tc.warning)
+			}
+		} else if expected := arrayOf("a", "1"); !Identical(sg.Post, expected) || len(diags) != 0 {
+			t.Errorf("%s: got Post %s, diagnostics %v", tc.contentType, dumpOrderedMap(sg.Post.ToOrderedMap()), diags)
+		}
+	}
+}
+
+func TestParseRequestContentDisposition(t *testing.T) {
+	testCases := []struct {
+		disposition string
+		expected    *Array
+	}{
+		{`form-data; name="a"`, arrayOf("a", "1")},
+		{`form-data; name=a[b]`, arrayOf("a", arrayOf("b", "1"))},
+		{`form-data; name=a b`, arrayOf("a", "1")},
+		{`form-data;name='a;b'`, arrayOf("a;b", "1")},
+		{`form-data; NAME="a\"b\\c"`, arrayOf("a\"b\\c", "1")},
+		{`form-data; name*=UTF-8''%C3%A9`, arrayOf()},
+		{`form-data; name="a"; filename=""`, arrayOf()},
+		{`form-data`, arrayOf()},
+	}
+	for _, tc := range testCases {
+		var body bytes.Buffer
+		w := multipart.NewWriter(&body)
+		part, _ := w.CreatePart(textproto.MIMEHeader{"Content-Disposition": {tc.disposition}})
+		part.Write([]byte("1"))
+		w.Close()
+
+		req := httptest.NewRequest("POST", "/", &body)
+		req.Header.Set("Content-Type", w.FormDataContentType())
+		sg, err := ParseRequest(req)
+		if err != nil {
+			t.Errorf("%s: ParseRequest returned error %v", tc.disposition, err)
+		} else if !Identical(sg.Post, tc.expected) {
+			t.Errorf("%s: Post = %s; want %s", tc.disposition, dumpOrderedMap(sg.Post.ToOrderedMap()), dumpOrderedMap(tc.expected.ToOrderedMap()))
+		}
+	}
+}
+
+func TestParseRequestLimits(t *testing.T) {
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	for _, name := range []string{"a", "b", "c", "d"} {
+		w.WriteField(name, "1")
+	}
+	w.Close()
+
+	testCases := []struct {
+		name        string
+		query       string
+		contentType string
+		body        string
+		get         *Array
+		post        *Array
+	}{
+		// $_GET stops before the variable which exceeds the limit.
+		{"get", "a=1&&b=2&c=3", "", "", arrayOf("a", "1", "b", "2"), arrayOf()},
+		// $_POST registers the variable which exceeds the limit, and counts
+		// empty pairs.
+		{"post", "", "application/x-www-form-urlencoded", "a=1&b=2&c=3&d=4", arrayOf(), arrayOf("a", "1", "b", "2", "c", "3")},
+		{"post with empty pairs", "", "application/x-www-form-urlencoded", "a=1&&c=3&d=4", arrayOf(), arrayOf("a", "1", "c", "3")},
+		{"multipart", "", w.FormDataContentType(), body.String(), arrayOf(), arrayOf("a", "1", "b", "1")},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/?"+tc.query, strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", tc.contentType)
+
+			var diags []Diagnostic
+			r := NewRuntime(PHP80).WithDiagnosticHandler(func(d Diagnostic) { diags = append(diags, d) })
+			r.INI.MaxInputVars = 2
+			sg, _ := r.ParseRequest(req)
+
+			if !Identical(sg.Get, tc.get) {
+				t.Errorf("Get = %s; want %s", dumpOrderedMap(sg.Get.ToOrderedMap()), dumpOrderedMap(tc.get.ToOrderedMap()))
+			}
+			if !Identical(sg.Post, tc.post) {
+				t.Errorf("Post = %s; want %s", dumpOrderedMap(sg.Post.ToOrderedMap()), dumpOrderedMap(tc.post.ToOrderedMap()))
+			}
+			if len(diags) != 1 || diags[0].Message != "Unknown: Input variables exceeded 2. To increase the limit change max_input_vars in php.ini." {
+				t.Errorf("emitted %v", diags)
+			}
+		})
+	}
+}
+
+func TestParseRequestOrder(t *testing.T) {
+	testCases := []struct {
+		variablesOrder string
+		requestOrder   string
+		request        *Array
+		cookie         *Array
+	}{
+		{"EGPCS", "", arrayOf("a", "cookie", "b", arrayOf("x", "post", "y", "cookie")), arrayOf("a", "cookie", "b", arrayOf("y", "cookie"))},
+		{"EGPCS", "pg", arrayOf("a", "get", "b", arrayOf("x", "get")), arrayOf("a", "cookie", "b", arrayOf("y", "cookie"))},
+		{"GPCS", "GPG", arrayOf("a", "post", "b", arrayOf("x", "post")), arrayOf("a", "cookie", "b", arrayOf("y", "cookie"))},
+		{"GP", "", arrayOf("a", "post", "b", arrayOf("x", "post")), arrayOf()},
+		{"GP", "CG", arrayOf("a", "get", "b", arrayOf("x", "get")), arrayOf()},
+		{"S", "", arrayOf(), arrayOf()},
+		{"", "", arrayOf("a", "cookie", "b", arrayOf("x", "post", "y", "cookie")), arrayOf("a", "cookie", "b", arrayOf("y", "cookie"))},
+	}
+	for _, tc := range testCases {
+		req := httptest.NewRequest(http.MethodPost, "/?a=get&b[x]=get", strings.NewReader("a=post&b[x]=post"))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.Header.Set("Cookie", "a=cookie; b[y]=cookie")
+
+		r := NewRuntime(PHP56)
+		r.INI.VariablesOrder = tc.variablesOrder
+		r.INI.RequestOrder = tc.requestOrder
+		sg, _ := r.ParseRequest(req)
+		if !Identical(sg.Request, tc.request) {
+			t.Errorf("Request with (%q, %q) = %s; want %s", tc.variablesOrder, tc.requestOrder, dumpOrderedMap(sg.Request.ToOrderedMap()), dumpOrderedMap(tc.request.ToOrderedMap()))
+		}
+		if !Identical(sg.Cookie, tc.cookie) {
+			t.Errorf("Cookie with (%q, %q) = %s; want %s", tc.variablesOrder, tc.requestOrder, dumpOrderedMap(sg.Cookie.ToOrderedMap()), dumpOrderedMap(tc.cookie.ToOrderedMap()))
+		}
+		if v, _ := sg.Get.Get("b"); !Identical(v, arrayOf("x", "get")) && strings.Contains(tc.variablesOrder, "G") {
+			t.Errorf("Get is modified by merging: %s", dumpOrderedMap(sg.Get.ToOrderedMap()))
+		}
+	}
+}
+
+func TestParseRequestUnclosedBracket(t *testing.T) {
+	req := httptest.NewRequest("POST", "/?a[=1&b[c][=2", strings.NewReader("d[ =3&e[f][ =4"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Cookie", "g[=5; h[i][=6")
+
+	sg, err := ParseRequest(req)
+	if err != nil {
+		t.Fatalf("ParseRequest returned error %v", err)
+	}
+	testCases := []struct {
+		name     string
+		result   *Array
+		expected *Array
+	}{
+		{"Get", sg.Get, arrayOf("a_", "1", "b", arrayOf("c", "2"))},
+		{"Post", sg.Post, arrayOf("d_ ", "3", "e", arrayOf("f", "4"))},
+		{"Cookie", sg.Cookie, arrayOf("g_", "5", "h", arrayOf("i", "6"))},
+	}
+	for _, tc := range testCases {
+		if !Identical(tc.result, tc.expected) {
+			t.Errorf("%s = %s; want %s", tc.name, dumpOrderedMap(tc.result.ToOrderedMap()), dumpOrderedMap(tc.expected.ToOrderedMap()))
+		}
+	}
+}
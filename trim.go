@@ -1,12 +1,8 @@
 package gophplib
 
-import (
-	"fmt"
-	"reflect"
-	"strings"
-)
+import "strings"
 
-// Trim is a ported function that works exactly the same as PHP 5.6's trim
+// Trim is a ported function that works exactly the same as PHP's trim
 // function. For more information, see the [official PHP documentation].
 //
 // In PHP 5.6, when attempting to use the trim() function with a data type other
@@ -17,8 +13,9 @@ import (
 // trim function in the package strings, see the [strings's trim documentation]
 //
 // This function returns error if given argument is not one of following:
-// string, int, int64, float64, bool, nil, and any type which does not implement
-// interface { toString() string }.
+// string, int, int64, float64, bool, nil, and any type which implements
+// Stringable. The error message is the same as the warning emitted by PHP, or the TypeError thrown
+// by PHP 8.
 //
 // NOTE: This function does not support the second parameter of original parse_str yet.
 // It only strips the default characters (" \n\r\t\v\x00")
@@ -38,11 +35,10 @@ import (
 //
 // [official PHP documentation]: https://www.php.net/manual/en/function.trim.php
 // [strings's trim documentation]: https://pkg.go.dev/strings#Trim
-func Trim(value any) (ret string, err error) {
+func (r *Runtime) Trim(value any) (ret string, err error) {
 	// Convert a value to string
-	characterString, err := zendParseArgAsString(value)
-	if err != nil {
-		err = fmt.Errorf("unsupported type : %s", reflect.TypeOf(value))
+	var characterString string
+	if err = r.ParseParameters(Function{"trim", []string{"string"}}, []any{value}, "s", &characterString); err != nil {
 		return
 	}
 
@@ -51,3 +47,8 @@ func Trim(value any) (ret string, err error) {
 	ret = strings.Trim(characterString, charSet)
 	return
 }
+
+// Trim works the same as Runtime.Trim of a Runtime emulating PHP 5.6.
+func Trim(value any) (string, error) {
+	return defaultRuntime.Trim(value)
+}
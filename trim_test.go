@@ -17,7 +17,7 @@ type Dog struct {
 	age  int
 }
 
-func (c Cat) toString() string {
+func (c Cat) ToString() string {
 	return fmt.Sprintf("name is %s and %d years old", c.name, c.age)
 }
 
@@ -92,10 +92,10 @@ func ExampleTrim_otherTypes() {
 	// Trim bool (false)
 	fmt.Println(Trim(false))
 
-	// Trim object has toString
+	// Trim object has ToString
 	fmt.Println(Trim(Cat{name: "nabi", age: 3}))
 
-	// Trim object has no toString
+	// Trim object has no ToString
 	fmt.Println(Trim(Dog{name: "choco", age: 5}))
 
 	// Trim function
@@ -117,16 +117,16 @@ func ExampleTrim_otherTypes() {
 	// 123 <nil>
 	// -123 <nil>
 	// 0 <nil>
-	//  unsupported type : []interface {}
+	//  trim() expects parameter 1 to be string, array given
 	// 1 <nil>
 	//  <nil>
 	// name is nabi and 3 years old <nil>
-	//  unsupported type : gophplib.Dog
+	//  trim() expects parameter 1 to be string, object given
 	// hello world <nil>
 	// <header>
 	//	<h1>hello world   </h1>
 	//</header> <nil>
-	//  unsupported type : *os.File
+	//  trim() expects parameter 1 to be string, resource given
 }
 
 func TestTrim(t *testing.T) {
@@ -164,7 +164,7 @@ func TestTrim(t *testing.T) {
 		{1230.12984732500000000000000000000000000, "1230.129847325"},
 		{123456789123456.40, "1.2345678912346E+14"},
 		{12345678912340.40, "12345678912340"},
-		{c, c.toString()},
+		{c, c.ToString()},
 		{customTrim(nil), "hello world"},
 		{`<header>
 	<h1>hello world   </h1>
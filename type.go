@@ -0,0 +1,191 @@
+package gophplib
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Gettype is a ported function that works exactly the same as PHP's gettype
+// function. It returns the PHP type name of the given value, which is one of
+// "boolean", "integer", "double", "string", "array", "object", "resource" and
+// "NULL". For more information, see the [official PHP documentation].
+//
+// Values are classified in the same way as the other functions of this
+// package, such as ConvertToString:
+//   - int, int8, int16, int32 and int64 are "integer", and float32 and float64
+//     are "double".
+//   - Arrays, slices, maps, ordered maps and Array are "array", unless they
+//     implement Stringable.
+//   - Structs, pointers to structs and any types which implement Stringable
+//     are "object".
+//   - *os.File, *net.Conn, *sql.DB and the types registered with
+//     RegisterResourceType are "resource".
+//
+// It returns "unknown type" for values which can not be represented in PHP,
+// such as functions and channels.
+//
+// Reference:
+//   - https://github.com/php/php-src/blob/php-5.6.40/ext/standard/type.c
+//   - https://github.com/php/php-src/blob/php-8.3.0/ext/standard/type.c
+//
+// [official PHP documentation]: https://www.php.net/manual/en/function.gettype.php
+func Gettype(value any) string {
+	switch zvalTypeOf(value) {
+	case typeNull:
+		return "NULL"
+	case typeBool:
+		return "boolean"
+	case typeLong:
+		return "integer"
+	case typeDouble:
+		return "double"
+	case typeString:
+		return "string"
+	case typeArray:
+		return "array"
+	case typeObject:
+		return "object"
+	case typeResource:
+		return "resource"
+	default:
+		return "unknown type"
+	}
+}
+
+// GetDebugType is a ported function that works exactly the same as PHP 8's
+// get_debug_type function. It returns the type name of the given value used in
+// PHP 8's error messages, which is one of "null", "bool", "int", "float",
+// "string", "array", the class name for objects, and "resource (stream)" for
+// resources, with the name of their resource types. For more information, see
+// the [official PHP documentation].
+//
+// The class name of an object is the name of its Go type without package path.
+// It returns "unknown" for values which can not be represented in PHP.
+//
+// Reference:
+//   - https://github.com/php/php-src/blob/php-8.3.0/ext/standard/type.c
+//
+// [official PHP documentation]: https://www.php.net/manual/en/function.get-debug-type.php
+func GetDebugType(value any) string {
+	switch zvalTypeOf(value) {
+	case typeUnsupported:
+		return "unknown"
+	case typeResource:
+		name, _ := resourceTypeOf(value)
+		return fmt.Sprintf("resource (%s)", name)
+	default:
+		return zendZvalTypeName(value)
+	}
+}
+
+// Settype is a ported function that works exactly the same as PHP's settype
+// function. It converts the value pointed by value to the given type in place,
+// in the same way as PHP's convert_to_* functions. For more information, see
+// the [official PHP documentation].
+//
+// typ is case-insensitive, and is one of following:
+//   - "boolean" or "bool": converted with ConvertToBool.
+//   - "integer" or "int": converted to int64 with Runtime.ConvertToLong.
+//   - "float" or "double": converted to float64 with ConvertToDouble.
+//   - "string": converted with Runtime.ConvertToString.
+//   - "array": converted to *Array. null becomes an empty array, and arrays are
+//     left untouched. Objects become arrays of their properties, whose keys
+//     are the names of the fields. Unexported fields are treated as private
+//     properties, whose names are prefixed with "\0ClassName\0" like PHP does.
+//     Any other values become an array which has the value as its only
+//     element.
+//   - "object": objects are left untouched. Converting the other values to
+//     stdClass is not supported, and it returns *Error in that case.
+//   - "null": set to nil.
+//
+// If typ is "resource" or is not a valid type, it returns *ValueError, whose
+// message is the same as what PHP reports. Before PHP 8, the message is also
+// emitted as a warning to the diagnostic handler of the Runtime. If the
+// conversion fails, it returns the error of the conversion. The value is left
+// untouched on error.
+//
+// Reference:
+//   - https://github.com/php/php-src/blob/php-5.6.40/ext/standard/type.c
+//   - https://github.com/php/php-src/blob/php-8.3.0/ext/standard/type.c
+//
+// [official PHP documentation]: https://www.php.net/manual/en/function.settype.php
+func (r *Runtime) Settype(value *any, typ string) error {
+	var converted any
+	var err error
+	switch strings.ToLower(typ) {
+	case "boolean", "bool":
+		converted = r.ConvertToBool(*value)
+	case "integer", "int":
+		converted, err = r.ConvertToLong(*value)
+	case "float", "double":
+		converted, err = r.ConvertToDouble(*value)
+	case "string":
+		converted, err = r.ConvertToString(*value)
+	case "array":
+		converted, err = convertToArray(*value)
+	case "object":
+		if zvalTypeOf(*value) != typeObject {
+			return &Error{Message: fmt.Sprintf("settype(): conversion of %s to object is not supported", zendZvalTypeName(*value))}
+		}
+		converted = *value
+	case "null":
+		converted = nil
+	case "resource":
+		if r.Version >= PHP80 {
+			return &ValueError{Function: "settype", Message: "Cannot convert to resource type"}
+		}
+		return r.warning(&ValueError{Function: "settype", Param: 2, ParamName: "type", Message: "settype(): Cannot convert to resource type"})
+	default:
+		if r.Version >= PHP80 {
+			return &ValueError{Function: "settype", Param: 2, ParamName: "type", Message: "settype(): Argument #2 ($type) must be a valid type"}
+		}
+		return r.warning(&ValueError{Function: "settype", Param: 2, ParamName: "type", Message: "settype(): Invalid type"})
+	}
+	if err != nil {
+		return err
+	}
+	*value = converted
+	return nil
+}
+
+// Settype works the same as Runtime.Settype of a Runtime emulating PHP 5.6.
+func Settype(value *any, typ string) error {
+	return defaultRuntime.Settype(value, typ)
+}
+
+// convertToArray is a ported function that works exactly the same as PHP's
+// convert_to_array function.
+//
+// Reference:
+//   - https://github.com/php/php-src/blob/php-5.6.40/Zend/zend_operators.c
+func convertToArray(value any) (any, error) {
+	switch zvalTypeOf(value) {
+	case typeNull:
+		return NewArray(), nil
+	case typeArray:
+		return value, nil
+	case typeObject:
+		arr := NewArray()
+		for _, prop := range objectProperties(value) {
+			key := prop.name
+			if !prop.public {
+				key = "\x00" + className(value) + "\x00" + prop.name
+			}
+			v := prop.value
+			if isCollectionType(v) {
+				var err error
+				if v, err = NewArrayFrom(v); err != nil {
+					return nil, err
+				}
+			}
+			arr.set(key, v)
+		}
+		return arr, nil
+	case typeUnsupported:
+		return nil, &UnsupportedTypeError{value}
+	default:
+		arr := NewArray()
+		arr.set(0, value)
+		return arr, nil
+	}
+}
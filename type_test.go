@@ -0,0 +1,194 @@
+package gophplib
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func ExampleGettype() {
+	fmt.Println(Gettype(1))
+	fmt.Println(Gettype(1.5))
+	fmt.Println(Gettype(nil))
+	fmt.Println(Gettype([]string{"a"}))
+	fmt.Println(Gettype(Point{1, 2}))
+	// Output:
+	// integer
+	// double
+	// NULL
+	// array
+	// object
+}
+
+func ExampleGetDebugType() {
+	fmt.Println(GetDebugType(1))
+	fmt.Println(GetDebugType(1.5))
+	fmt.Println(GetDebugType(nil))
+	fmt.Println(GetDebugType(&Point{1, 2}))
+	fmt.Println(GetDebugType(getFile()))
+	// Output:
+	// int
+	// float
+	// null
+	// Point
+	// resource (stream)
+}
+
+func ExampleSettype() {
+	var value any = "12abc"
+	fmt.Println(Settype(&value, "integer"), value)
+	fmt.Println(Settype(&value, "array"), value.(*Array).Values())
+	fmt.Println(Settype(&value, "resource"), value.(*Array).Values())
+	// Output:
+	// <nil> 12
+	// <nil> [12]
+	// settype(): Cannot convert to resource type [12]
+}
+
+func TestGettype(t *testing.T) {
+	testCases := []struct {
+		value     any
+		gettype   string
+		debugType string
+	}{
+		{nil, "NULL", "null"},
+		{true, "boolean", "bool"},
+		{int8(1), "integer", "int"},
+		{float32(1), "double", "float"},
+		{"a", "string", "string"},
+		{[2]int{1, 2}, "array", "array"},
+		{map[string]int{}, "array", "array"},
+		{NewArray(), "array", "array"},
+		{Dog{}, "object", "Dog"},
+		{&Bird{}, "object", "Bird"},
+		{Sample{}, "object", "Sample"},
+		{&curlHandle{}, "resource", "resource (curl)"},
+		{func() {}, "unknown type", "unknown"},
+	}
+	for _, tc := range testCases {
+		if s := Gettype(tc.value); s != tc.gettype {
+			t.Errorf("Gettype(%T) = %q; want %q", tc.value, s, tc.gettype)
+		}
+		if s := GetDebugType(tc.value); s != tc.debugType {
+			t.Errorf("GetDebugType(%T) = %q; want %q", tc.value, s, tc.debugType)
+		}
+	}
+}
+
+func TestSettype(t *testing.T) {
+	testCases := []struct {
+		value    any
+		typ      string
+		expected any
+	}{
+		{"1e3", "int", int64(1)},
+		{"1.5abc", "float", 1.5},
+		{"0", "BOOLEAN", false},
+		{[]int{}, "bool", false},
+		{1.0, "string", "1"},
+		{nil, "string", ""},
+		{true, "Null", nil},
+		{Point{1, 2}, "object", Point{1, 2}},
+	}
+	for _, tc := range testCases {
+		value := tc.value
+		if err := Settype(&value, tc.typ); err != nil {
+			t.Errorf("Settype(%v, %q) returned error %v", tc.value, tc.typ, err)
+			continue
+		}
+		if !Identical(value, tc.expected) {
+			t.Errorf("Settype(%v, %q) = %#v; want %#v", tc.value, tc.typ, value, tc.expected)
+		}
+	}
+}
+
+func TestSettypeObject(t *testing.T) {
+	testCases := []struct {
+		version Version
+		typ     string
+		level   ErrorLevel
+		message string
+	}{
+		{PHP56, "float", ENotice, "Object of class Dog could not be converted to double"},
+		{PHP74, "int", ENotice, "Object of class Dog could not be converted to int"},
+		{PHP80, "double", EWarning, "Object of class Dog could not be converted to float"},
+	}
+	for _, tc := range testCases {
+		var diags []Diagnostic
+		r := NewRuntime(tc.version).WithDiagnosticHandler(func(d Diagnostic) { diags = append(diags, d) })
+		var v any = Dog{"choco", 5}
+		if err := r.Settype(&v, tc.typ); err != nil || (v != int64(1) && v != 1.0) {
+			t.Errorf("Settype(%q) on %d = (%v, %v); want 1", tc.typ, tc.version, v, err)
+		}
+		if len(diags) != 1 || diags[0].Level != tc.level || diags[0].Message != tc.message {
+			t.Errorf("Settype(%q) on %d emitted %v; want %q", tc.typ, tc.version, diags, tc.message)
+		}
+	}
+}
+
+func TestSettypeArray(t *testing.T) {
+	testCases := []struct {
+		value    any
+		expected []arrayEntry
+	}{
+		{nil, nil},
+		{"a", []arrayEntry{{0, "a"}}},
+		{1.5, []arrayEntry{{0, 1.5}}},
+		{Point{1, 2}, []arrayEntry{{"X", 1}, {"Y", 2}}},
+		{&Dog{"choco", 5}, []arrayEntry{{"\x00Dog\x00name", "choco"}, {"\x00Dog\x00age", int64(5)}}},
+	}
+	for _, tc := range testCases {
+		value := tc.value
+		if err := Settype(&value, "array"); err != nil {
+			t.Errorf("Settype(%v, array) returned error %v", tc.value, err)
+			continue
+		}
+		arr, ok := value.(*Array)
+		if !ok {
+			t.Errorf("Settype(%v, array) = %T; want *Array", tc.value, value)
+			continue
+		}
+		if entries := aggregateEntries(arr); fmt.Sprint(entries) != fmt.Sprint(tc.expected) {
+			t.Errorf("Settype(%v, array) = %v; want %v", tc.value, entries, tc.expected)
+		}
+	}
+
+	arr := NewArray()
+	var value any = arr
+	if err := Settype(&value, "array"); err != nil || value != arr {
+		t.Errorf("Settype(*Array, array) = (%v, %v); want the same array", value, err)
+	}
+}
+
+func TestSettypeError(t *testing.T) {
+	testCases := []struct {
+		version Version
+		value   any
+		typ     string
+		err     string
+		target  error
+	}{
+		{PHP56, 1, "resource", "settype(): Cannot convert to resource type", ErrValueError},
+		{PHP80, 1, "resource", "Cannot convert to resource type", ErrValueError},
+		{PHP56, 1, "long", "settype(): Invalid type", ErrValueError},
+		{PHP80, 1, "long", "settype(): Argument #2 ($type) must be a valid type", ErrValueError},
+		{PHP80, Dog{}, "string", "Object of class Dog could not be converted to string", ErrError},
+		{PHP80, func() {}, "array", "unsupported type : func()", ErrUnsupportedType},
+	}
+	for _, tc := range testCases {
+		value := tc.value
+		err := NewRuntime(tc.version).Settype(&value, tc.typ)
+		if err == nil || err.Error() != tc.err || !errors.Is(err, tc.target) {
+			t.Errorf("Settype(%v, %q) on %d returned error %v; want %q", tc.value, tc.typ, tc.version, err, tc.err)
+		}
+		if fmt.Sprint(value) != fmt.Sprint(tc.value) {
+			t.Errorf("Settype(%v, %q) on %d changed the value to %v", tc.value, tc.typ, tc.version, value)
+		}
+	}
+
+	var value any = 1
+	var e *Error
+	if err := Settype(&value, "object"); !errors.As(err, &e) || err.Error() != "settype(): conversion of int to object is not supported" {
+		t.Errorf("Settype(1, object) returned error %v; want *Error", err)
+	}
+}
@@ -13,19 +13,73 @@ import (
 // of RFC 3986 since it decodes '+' to ' '. This is done to be compatible with
 // PHP's urldecode function.
 //
+// The decoded bytes are returned as is, so the result is not valid UTF-8 if the
+// input encodes text in other encodings, such as EUC-KR. (ex: "%B0%A1" is
+// decoded to "\xb0\xa1") Use UrldecodeValidUTF8 to replace invalid UTF-8
+// sequences instead.
+//
 // References:
 //   - https://www.php.net/manual/en/function.urldecode.php
 //   - https://github.com/php/php-src/blob/php-5.6.40/ext/standard/url.c#L513-L561
 //   - https://github.com/php/php-src/blob/php-8.3.0/ext/standard/url.c#L578-L618
 //
+// The value is converted to string like Strlen does. It returns error if the
+// value can not be converted to string, with the same message as the warning
+// emitted by PHP, or the TypeError thrown by PHP 8.
+//
 // [official PHP documentation]: https://www.php.net/manual/en/function.urldecode.php
+func (r *Runtime) Urldecode(value any) (string, error) {
+	var str string
+	if err := r.ParseParameters(Function{"urldecode", []string{"string"}}, []any{value}, "s", &str); err != nil {
+		return "", err
+	}
+	return urldecode(str, true), nil
+}
+
+// Urldecode works the same as Runtime.Urldecode, except that it accepts only
+// strings, so it never fails.
 func Urldecode(input string) string {
+	return urldecode(input, true)
+}
+
+// Rawurldecode is a ported function that works exactly the same as PHP's
+// rawurldecode function. For more information, see the [official PHP
+// documentation].
+//
+// It works the same as Urldecode, except that it does not decode '+' to ' ',
+// and the value is converted to string like Strlen does. It returns error if
+// the value can not be converted to string, with the same message as the
+// warning emitted by PHP, or the TypeError thrown by PHP 8.
+//
+// References:
+//   - https://github.com/php/php-src/blob/php-5.6.40/ext/standard/url.c
+//   - https://github.com/php/php-src/blob/php-8.3.0/ext/standard/url.c
+//
+// [official PHP documentation]: https://www.php.net/manual/en/function.rawurldecode.php
+func (r *Runtime) Rawurldecode(value any) (string, error) {
+	var str string
+	if err := r.ParseParameters(Function{"rawurldecode", []string{"string"}}, []any{value}, "s", &str); err != nil {
+		return "", err
+	}
+	return urldecode(str, false), nil
+}
+
+// Rawurldecode works the same as Runtime.Rawurldecode, except that it accepts
+// only strings, so it never fails.
+func Rawurldecode(input string) string {
+	return urldecode(input, false)
+}
+
+// urldecode is a ported function that works exactly the same as PHP's
+// php_url_decode function if plus is true, and php_raw_url_decode function
+// otherwise.
+func urldecode(input string, plus bool) string {
 	buf := []byte(input)
 	length := len(buf)
 
 	j := 0
 	for i := 0; i < length; i, j = i+1, j+1 {
-		if buf[i] == '+' {
+		if plus && buf[i] == '+' {
 			buf[j] = ' '
 		} else if buf[i] == '%' && i+2 < length && isxdigit(buf[i+1]) && isxdigit(buf[i+2]) {
 			buf[j] = htoi(buf[i+1], buf[i+2])
@@ -35,7 +89,15 @@ func Urldecode(input string) string {
 		}
 	}
 
-	return strings.ToValidUTF8(string(buf[:j]), "�")
+	return string(buf[:j])
+}
+
+// UrldecodeValidUTF8 works the same as Urldecode, except that it replaces each
+// run of bytes which are not valid UTF-8 in the result with the replacement
+// character U+FFFD. PHP never does this, so the result may differ from PHP's.
+// Use it only if the result must be valid UTF-8.
+func UrldecodeValidUTF8(input string) string {
+	return strings.ToValidUTF8(Urldecode(input), "\uFFFD")
 }
 
 // isxdigit is a ported function that works exactly the same as C's isxdigit
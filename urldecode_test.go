@@ -7,10 +7,18 @@ import (
 
 func ExampleUrldecode() {
 	fmt.Println(Urldecode("my=apples&are=green+and%20red%F0%9F%8D%8E"))
-	fmt.Println(Urldecode("foo.php?myvar=%BA"))
+	fmt.Printf("%q\n", Urldecode("foo.php?myvar=%BA"))
 	// Output:
 	// my=apples&are=green and red🍎
+	// "foo.php?myvar=\xba"
+}
+
+func ExampleUrldecodeValidUTF8() {
+	fmt.Println(UrldecodeValidUTF8("foo.php?myvar=%BA"))
+	fmt.Println(UrldecodeValidUTF8("name=%B0%A1%B3%AA"))
+	// Output:
 	// foo.php?myvar=�
+	// name=�
 }
 
 // Test cases for Urldecode. These tests were created using the following test
@@ -163,7 +171,17 @@ func TestUrldecode(t *testing.T) {
 		{
 			name:     "DecodeSingleByteInvalidUTF8",
 			input:    "foo.php?myvar=%BA",
-			expected: "foo.php?myvar=�",
+			expected: "foo.php?myvar=\xba",
+		},
+		{
+			name:     "DecodeEUCKR",
+			input:    "name=%B0%A1%B3%AA",
+			expected: "name=\xb0\xa1\xb3\xaa",
+		},
+		{
+			name:     "RawInvalidUTF8",
+			input:    "\xb0\xa1+%2B",
+			expected: "\xb0\xa1 +",
 		},
 		{
 			name:     "DecodeValidTwoByteUTF8Character",
@@ -189,6 +207,43 @@ actual    %#v`, tc.expected, result)
 	}
 }
 
+func ExampleRawurldecode() {
+	fmt.Println(Rawurldecode("foo+bar%20baz%7E"))
+	// Output:
+	// foo+bar baz~
+}
+
+func TestRawurldecode(t *testing.T) {
+	testCases := []struct {
+		input    any
+		expected string
+	}{
+		{"", ""},
+		{"a+b", "a+b"},
+		{"a%2Bb%20c", "a+b c"},
+		{"%B0%A1", "\xb0\xa1"},
+		{"%", "%"},
+		{"%2", "%2"},
+		{"%zz%41", "%zzA"},
+		{123, "123"},
+	}
+	for _, tc := range testCases {
+		if s, err := defaultRuntime.Rawurldecode(tc.input); err != nil || s != tc.expected {
+			t.Errorf("Rawurldecode(%#v) = (%q, %v); want %q", tc.input, s, err, tc.expected)
+		}
+		if str, ok := tc.input.(string); ok {
+			if s := Rawurldecode(str); s != tc.expected {
+				t.Errorf("Rawurldecode(%q) = %q; want %q", str, s, tc.expected)
+			}
+		}
+	}
+
+	_, err := NewRuntime(PHP80).Rawurldecode([]int{})
+	if expected := "rawurldecode(): Argument #1 ($string) must be of type string, array given"; err == nil || err.Error() != expected {
+		t.Errorf("Rawurldecode([]int{}) returned error %v; want %q", err, expected)
+	}
+}
+
 func TestIsxdigit(t *testing.T) {
 	cases := []struct {
 		byte
@@ -256,3 +311,29 @@ func TestHtoi(t *testing.T) {
 		})
 	}
 }
+
+func TestUrldecodeRuntime(t *testing.T) {
+	testCases := []struct {
+		version  Version
+		value    any
+		expected string
+		err      string
+	}{
+		{PHP56, "a+b%20c", "a b c", ""},
+		{PHP80, 1.5, "1.5", ""},
+		{PHP56, []int{}, "", "urldecode() expects parameter 1 to be string, array given"},
+		{PHP80, Dog{}, "", "urldecode(): Argument #1 ($string) must be of type string, Dog given"},
+	}
+	for _, tc := range testCases {
+		s, err := NewRuntime(tc.version).Urldecode(tc.value)
+		if tc.err != "" {
+			if err == nil || err.Error() != tc.err {
+				t.Errorf("Urldecode(%v) on %d returned error %v; want %q", tc.value, tc.version, err, tc.err)
+			}
+			continue
+		}
+		if err != nil || s != tc.expected {
+			t.Errorf("Urldecode(%v) on %d = (%q, %v); want %q", tc.value, tc.version, s, err, tc.expected)
+		}
+	}
+}
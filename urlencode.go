@@ -0,0 +1,88 @@
+package gophplib
+
+// Urlencode is a ported function that works exactly the same as PHP's urlencode
+// function. For more information, see the [official PHP documentation].
+//
+// It encodes the given value in the same way as application/x-www-form-urlencoded
+// media type of RFC 1738. Alphanumeric characters and "-_." are left as is,
+// spaces are encoded as '+', and all the other bytes are encoded as '%'
+// followed by two uppercase hexadecimal digits. Note that it differs from
+// net/url's QueryEscape function, which leaves '~' as is. PHP encodes '~' to
+// "%7E" and '*' to "%2A".
+//
+// The value is converted to string like Strlen does. It returns error if the
+// value can not be converted to string, with the same message as the warning
+// emitted by PHP, or the TypeError thrown by PHP 8.
+//
+// References:
+//   - https://github.com/php/php-src/blob/php-5.6.40/ext/standard/url.c
+//   - https://github.com/php/php-src/blob/php-8.3.0/ext/standard/url.c
+//
+// [official PHP documentation]: https://www.php.net/manual/en/function.urlencode.php
+func (r *Runtime) Urlencode(value any) (string, error) {
+	var str string
+	if err := r.ParseParameters(Function{"urlencode", []string{"string"}}, []any{value}, "s", &str); err != nil {
+		return "", err
+	}
+	return urlencode(str, false), nil
+}
+
+// Urlencode works the same as Runtime.Urlencode, except that it accepts only
+// strings, so it never fails.
+func Urlencode(input string) string {
+	return urlencode(input, false)
+}
+
+// Rawurlencode is a ported function that works exactly the same as PHP's
+// rawurlencode function. For more information, see the [official PHP
+// documentation].
+//
+// It encodes the given value according to RFC 3986. Alphanumeric characters
+// and "-_.~" are left as is, and all the other bytes including spaces are
+// encoded as '%' followed by two uppercase hexadecimal digits.
+//
+// The value is converted to string like Strlen does. It returns error if the
+// value can not be converted to string, with the same message as the warning
+// emitted by PHP, or the TypeError thrown by PHP 8.
+//
+// References:
+//   - https://github.com/php/php-src/blob/php-5.6.40/ext/standard/url.c
+//   - https://github.com/php/php-src/blob/php-8.3.0/ext/standard/url.c
+//
+// [official PHP documentation]: https://www.php.net/manual/en/function.rawurlencode.php
+func (r *Runtime) Rawurlencode(value any) (string, error) {
+	var str string
+	if err := r.ParseParameters(Function{"rawurlencode", []string{"string"}}, []any{value}, "s", &str); err != nil {
+		return "", err
+	}
+	return urlencode(str, true), nil
+}
+
+// Rawurlencode works the same as Runtime.Rawurlencode, except that it accepts
+// only strings, so it never fails.
+func Rawurlencode(input string) string {
+	return urlencode(input, true)
+}
+
+// urlencode is a ported function that works exactly the same as PHP's
+// php_raw_url_encode function if raw is true, and php_url_encode function
+// otherwise.
+func urlencode(input string, raw bool) string {
+	const hexchars = "0123456789ABCDEF"
+
+	buf := make([]byte, 0, len(input))
+	for i := 0; i < len(input); i++ {
+		c := input[i]
+		switch {
+		case c == ' ' && !raw:
+			buf = append(buf, '+')
+		case '0' <= c && c <= '9', 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', c == '-', c == '.', c == '_':
+			buf = append(buf, c)
+		case c == '~' && raw:
+			buf = append(buf, c)
+		default:
+			buf = append(buf, '%', hexchars[c>>4], hexchars[c&15])
+		}
+	}
+	return string(buf)
+}
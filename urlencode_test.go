@@ -0,0 +1,106 @@
+package gophplib
+
+import (
+	"fmt"
+	"testing"
+)
+
+func ExampleUrlencode() {
+	fmt.Println(Urlencode("foo bar@baz~*"))
+	// Output:
+	// foo+bar%40baz%7E%2A
+}
+
+func ExampleRuntime_Urlencode() {
+	r := NewRuntime(PHP56)
+	fmt.Println(r.Urlencode(1.5))
+	fmt.Println(r.Urlencode([]int{}))
+	// Output:
+	// 1.5 <nil>
+	//  urlencode() expects parameter 1 to be string, array given
+}
+
+func ExampleRawurlencode() {
+	fmt.Println(Rawurlencode("foo bar@baz~*"))
+	// Output:
+	// foo%20bar%40baz~%2A
+}
+
+// Test cases for Urlencode and Rawurlencode. These tests were created using
+// the following test cases in PHP as inspiration.
+//
+// Reference:
+//   - https://www.php.net/manual/en/function.urlencode.php
+//   - https://www.php.net/manual/en/function.rawurlencode.php
+func TestUrlencode(t *testing.T) {
+	testCases := []struct {
+		input    any
+		expected string
+		raw      string
+	}{
+		{"", "", ""},
+		{"abcXYZ019", "abcXYZ019", "abcXYZ019"},
+		{"-_.", "-_.", "-_."},
+		{"~", "%7E", "~"},
+		{" ", "+", "%20"},
+		{"+", "%2B", "%2B"},
+		{"!*'();:@&=$,/?#[]", "%21%2A%27%28%29%3B%3A%40%26%3D%24%2C%2F%3F%23%5B%5D", "%21%2A%27%28%29%3B%3A%40%26%3D%24%2C%2F%3F%23%5B%5D"},
+		{"\x00\x7f\x80\xff", "%00%7F%80%FF", "%00%7F%80%FF"},
+		{"한", "%ED%95%9C", "%ED%95%9C"},
+		{"\xb0\xa1", "%B0%A1", "%B0%A1"},
+		{nil, "", ""},
+		{true, "1", "1"},
+		{-1.5, "-1.5", "-1.5"},
+		{Cat{"nabi", 3}, "name+is+nabi+and+3+years+old", "name%20is%20nabi%20and%203%20years%20old"},
+	}
+	r := NewRuntime(PHP56)
+	for _, tc := range testCases {
+		if s, err := r.Urlencode(tc.input); err != nil || s != tc.expected {
+			t.Errorf("Urlencode(%#v) = (%q, %v); want %q", tc.input, s, err, tc.expected)
+		}
+		if s, err := r.Rawurlencode(tc.input); err != nil || s != tc.raw {
+			t.Errorf("Rawurlencode(%#v) = (%q, %v); want %q", tc.input, s, err, tc.raw)
+		}
+		if str, ok := tc.input.(string); ok {
+			if s := Urlencode(str); s != tc.expected {
+				t.Errorf("Urlencode(%q) = %q; want %q", str, s, tc.expected)
+			}
+			if s := Rawurlencode(str); s != tc.raw {
+				t.Errorf("Rawurlencode(%q) = %q; want %q", str, s, tc.raw)
+			}
+		}
+	}
+}
+
+func TestUrlencodeRoundTrip(t *testing.T) {
+	var input []byte
+	for c := 0; c < 256; c++ {
+		input = append(input, byte(c))
+	}
+
+	if decoded := Urldecode(Urlencode(string(input))); decoded != string(input) {
+		t.Errorf("Urldecode(Urlencode(x)) = %q; want %q", decoded, input)
+	}
+
+	if decoded := Rawurldecode(Rawurlencode(string(input))); decoded != string(input) {
+		t.Errorf("Rawurldecode(Rawurlencode(x)) = %q; want %q", decoded, input)
+	}
+}
+
+func TestUrlencodeError(t *testing.T) {
+	testCases := []struct {
+		version Version
+		fn      func(r *Runtime) (string, error)
+		err     string
+	}{
+		{PHP56, func(r *Runtime) (string, error) { return r.Urlencode([]int{}) },
+			"urlencode() expects parameter 1 to be string, array given"},
+		{PHP80, func(r *Runtime) (string, error) { return r.Rawurlencode(Dog{}) },
+			"rawurlencode(): Argument #1 ($string) must be of type string, Dog given"},
+	}
+	for _, tc := range testCases {
+		if s, err := tc.fn(NewRuntime(tc.version)); err == nil || err.Error() != tc.err || s != "" {
+			t.Errorf("got (%q, %v); want error %q", s, err, tc.err)
+		}
+	}
+}
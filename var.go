@@ -0,0 +1,481 @@
+package gophplib
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// VarDump is a ported function that works exactly the same as PHP's var_dump
+// function. It writes the structured information of the given values to w,
+// including their types, in the same format as PHP. For more information, see
+// the [official PHP documentation].
+//
+//	array(2) {
+//	  ["a"]=>
+//	  int(1)
+//	  [0]=>
+//	  string(3) "foo"
+//	}
+//
+// Floats are formatted with the "precision" INI setting before PHP 7.1, and
+// with the "serialize_precision" INI setting since PHP 7.1. Lengths of strings
+// are the number of bytes.
+//
+// Objects are printed with their handles, such as "object(Dog)#1 (2)". Go has
+// no object handles, so handles are assigned in the order the objects appear
+// in a single call, starting from 1. Pointers to the same struct are given the
+// same handle. Resources are printed with their resource IDs assigned by the
+// ResourceRegistry of the Runtime.
+//
+// It returns error without writing anything if any of the values is not
+// supported by this package, or the error from w.
+//
+// Reference:
+//   - https://github.com/php/php-src/blob/php-5.6.40/ext/standard/var.c
+//   - https://github.com/php/php-src/blob/php-8.3.0/ext/standard/var.c
+//
+// [official PHP documentation]: https://www.php.net/manual/en/function.var-dump.php
+func (r *Runtime) VarDump(w io.Writer, values ...any) error {
+	p := r.newVarPrinter()
+	for _, value := range values {
+		if err := p.varDump(value, 1); err != nil {
+			return err
+		}
+	}
+	return p.writeTo(w)
+}
+
+// VarDump works the same as Runtime.VarDump of a Runtime emulating PHP 5.6.
+func VarDump(w io.Writer, values ...any) error {
+	return defaultRuntime.VarDump(w, values...)
+}
+
+// PrintR is a ported function that works exactly the same as PHP's print_r
+// function. It writes human-readable information of the given value to w, in
+// the same format as PHP. For more information, see the [official PHP
+// documentation].
+//
+//	Array
+//	(
+//	    [a] => 1
+//	    [0] => foo
+//	)
+//
+// Values other than arrays and objects are converted to strings with
+// Runtime.ConvertToString.
+//
+// It returns error without writing anything if the value is not supported by
+// this package, or the error from w.
+//
+// Reference:
+//   - https://github.com/php/php-src/blob/php-5.6.40/Zend/zend.c
+//   - https://github.com/php/php-src/blob/php-8.3.0/Zend/zend.c
+//
+// [official PHP documentation]: https://www.php.net/manual/en/function.print-r.php
+func (r *Runtime) PrintR(w io.Writer, value any) error {
+	p := r.newVarPrinter()
+	if err := p.printR(value, 0); err != nil {
+		return err
+	}
+	return p.writeTo(w)
+}
+
+// PrintR works the same as Runtime.PrintR of a Runtime emulating PHP 5.6.
+func PrintR(w io.Writer, value any) error {
+	return defaultRuntime.PrintR(w, value)
+}
+
+// VarExport is a ported function that works exactly the same as PHP's
+// var_export function. It writes the parsable string representation of the
+// given value to w, in the same format as PHP. For more information, see the
+// [official PHP documentation].
+//
+//	array (
+//	  'a' => 1,
+//	  0 => 'foo',
+//	)
+//
+// The differences between PHP versions are as follows:
+//   - Floats are formatted with the "serialize_precision" INI setting, and PHP
+//     7 and later append ".0" to floats which look like integers.
+//   - PHP 7.3 and later prefix the class names of objects with '\'.
+//   - PHP 8 writes math.MinInt64 as "-9223372036854775807-1".
+//
+// Resources are written as "NULL". Arrays which contain themselves are written
+// as "NULL" as well, and "var_export does not handle circular references" is
+// emitted as E_WARNING to the diagnostic handler of the Runtime.
+//
+// It returns error without writing anything if the value is not supported by
+// this package, or the error from w.
+//
+// Reference:
+//   - https://github.com/php/php-src/blob/php-5.6.40/ext/standard/var.c
+//   - https://github.com/php/php-src/blob/php-8.3.0/ext/standard/var.c
+//
+// [official PHP documentation]: https://www.php.net/manual/en/function.var-export.php
+func (r *Runtime) VarExport(w io.Writer, value any) error {
+	p := r.newVarPrinter()
+	if err := p.varExport(value, 1); err != nil {
+		return err
+	}
+	return p.writeTo(w)
+}
+
+// VarExport works the same as Runtime.VarExport of a Runtime emulating PHP 5.6.
+func VarExport(w io.Writer, value any) error {
+	return defaultRuntime.VarExport(w, value)
+}
+
+// varPrinter holds the state of VarDump, PrintR and VarExport. The output is
+// buffered, so that nothing is written if any value is not supported.
+type varPrinter struct {
+	r   *Runtime
+	buf strings.Builder
+
+	// handles is the object handles assigned to pointers to structs.
+	handles    map[uintptr]int
+	nextHandle int
+
+	// visiting is the arrays and the objects being printed, which are used to
+	// detect recursion.
+	visiting map[any]bool
+}
+
+func (r *Runtime) newVarPrinter() *varPrinter {
+	return &varPrinter{r: r, handles: make(map[uintptr]int), nextHandle: 1, visiting: make(map[any]bool)}
+}
+
+func (p *varPrinter) writeTo(w io.Writer) error {
+	_, err := io.WriteString(w, p.buf.String())
+	return err
+}
+
+// indent writes n spaces.
+func (p *varPrinter) indent(n int) {
+	p.buf.WriteString(strings.Repeat(" ", n))
+}
+
+// handleOf returns the object handle of the given object.
+func (p *varPrinter) handleOf(object any) int {
+	v := reflect.ValueOf(object)
+	if v.Kind() == reflect.Pointer {
+		if handle, ok := p.handles[v.Pointer()]; ok {
+			return handle
+		}
+		p.handles[v.Pointer()] = p.nextHandle
+	}
+	p.nextHandle++
+	return p.nextHandle - 1
+}
+
+// enter marks the given array or object as being printed, and reports whether
+// it is not already being printed.
+func (p *varPrinter) enter(value any) bool {
+	key, ok := recursionKey(value)
+	if !ok {
+		return true
+	}
+	if p.visiting[key] {
+		return false
+	}
+	p.visiting[key] = true
+	return true
+}
+
+// leave unmarks the given array or object marked by enter.
+func (p *varPrinter) leave(value any) {
+	if key, ok := recursionKey(value); ok {
+		delete(p.visiting, key)
+	}
+}
+
+// recursionRef identifies a map or a slice by its type and the address of its
+// content, since they are not comparable.
+type recursionRef struct {
+	typ reflect.Type
+	ptr uintptr
+	len int
+}
+
+// recursionKey returns the key which identifies the given array or object
+// while it is being visited, and reports whether it can contain itself. Values
+// which are neither pointers, maps nor slices are copied when they are
+// stored, so they can not contain themselves.
+func recursionKey(value any) (any, bool) {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Pointer:
+		return value, !v.IsNil()
+	case reflect.Map, reflect.Slice:
+		if v.Pointer() == 0 {
+			return nil, false
+		}
+		return recursionRef{v.Type(), v.Pointer(), v.Len()}, true
+	default:
+		return nil, false
+	}
+}
+
+// formatDouble formats the given float like PHP's snprintf with "%.*H" format,
+// and appends ".0" if zeroFraction is true and it looks like an integer.
+func formatDouble(d float64, precision int, zeroFraction bool) string {
+	switch {
+	case math.IsNaN(d):
+		return "NAN"
+	case math.IsInf(d, 1):
+		return "INF"
+	case math.IsInf(d, -1):
+		return "-INF"
+	}
+	return string(smartStrAppendDouble(nil, d, precision, zeroFraction))
+}
+
+// varDump is a ported function that works exactly the same as PHP's
+// php_var_dump function.
+func (p *varPrinter) varDump(value any, level int) error {
+	if level > 1 {
+		p.indent(level - 1)
+	}
+
+	switch zvalTypeOf(value) {
+	case typeNull:
+		p.buf.WriteString("NULL\n")
+	case typeBool:
+		fmt.Fprintf(&p.buf, "bool(%t)\n", value.(bool))
+	case typeLong:
+		fmt.Fprintf(&p.buf, "int(%d)\n", longOf(value))
+	case typeDouble:
+		precision := p.r.ini().SerializePrecision
+		if p.r.Version < PHP71 {
+			precision = p.r.ini().Precision
+		}
+		fmt.Fprintf(&p.buf, "float(%s)\n", formatDouble(doubleOf(value), precision, false))
+	case typeString:
+		s := value.(string)
+		fmt.Fprintf(&p.buf, "string(%d) \"%s\"\n", len(s), s)
+	case typeArray:
+		if !p.enter(value) {
+			p.buf.WriteString("*RECURSION*\n")
+			return nil
+		}
+		defer p.leave(value)
+
+		entries := aggregateEntries(value)
+		fmt.Fprintf(&p.buf, "array(%d) {\n", len(entries))
+		for _, e := range entries {
+			p.indent(level + 1)
+			if k, ok := e.key.(string); ok {
+				fmt.Fprintf(&p.buf, "[\"%s\"]=>\n", k)
+			} else {
+				fmt.Fprintf(&p.buf, "[%v]=>\n", e.key)
+			}
+			if err := p.varDump(e.value, level+2); err != nil {
+				return err
+			}
+		}
+		if level > 1 {
+			p.indent(level - 1)
+		}
+		p.buf.WriteString("}\n")
+	case typeObject:
+		if !p.enter(value) {
+			p.buf.WriteString("*RECURSION*\n")
+			return nil
+		}
+		defer p.leave(value)
+
+		props := objectProperties(value)
+		name := className(value)
+		fmt.Fprintf(&p.buf, "object(%s)#%d (%d) {\n", name, p.handleOf(value), len(props))
+		for _, prop := range props {
+			p.indent(level + 1)
+			if prop.public {
+				fmt.Fprintf(&p.buf, "[\"%s\"]=>\n", prop.name)
+			} else {
+				fmt.Fprintf(&p.buf, "[\"%s\":\"%s\":private]=>\n", prop.name, name)
+			}
+			if err := p.varDump(prop.value, level+2); err != nil {
+				return err
+			}
+		}
+		if level > 1 {
+			p.indent(level - 1)
+		}
+		p.buf.WriteString("}\n")
+	case typeResource:
+		id, _ := p.r.resourceID(value)
+		name, _ := resourceTypeOf(value)
+		fmt.Fprintf(&p.buf, "resource(%d) of type (%s)\n", id, name)
+	default:
+		return &UnsupportedTypeError{value}
+	}
+	return nil
+}
+
+// printR is a ported function that works exactly the same as PHP's
+// print_zval_r_to_buf function.
+func (p *varPrinter) printR(value any, indent int) error {
+	switch zvalTypeOf(value) {
+	case typeArray:
+		p.buf.WriteString("Array\n")
+		if !p.enter(value) {
+			p.buf.WriteString(" *RECURSION*")
+			return nil
+		}
+		defer p.leave(value)
+
+		entries := aggregateEntries(value)
+		p.printHashStart(indent)
+		for _, e := range entries {
+			p.indent(indent + 4)
+			fmt.Fprintf(&p.buf, "[%v] => ", e.key)
+			if err := p.printR(e.value, indent+8); err != nil {
+				return err
+			}
+			p.buf.WriteByte('\n')
+		}
+		p.printHashEnd(indent)
+	case typeObject:
+		name := className(value)
+		fmt.Fprintf(&p.buf, "%s Object\n", name)
+		if !p.enter(value) {
+			p.buf.WriteString(" *RECURSION*")
+			return nil
+		}
+		defer p.leave(value)
+
+		p.printHashStart(indent)
+		for _, prop := range objectProperties(value) {
+			p.indent(indent + 4)
+			if prop.public {
+				fmt.Fprintf(&p.buf, "[%s] => ", prop.name)
+			} else {
+				fmt.Fprintf(&p.buf, "[%s:%s:private] => ", prop.name, name)
+			}
+			if err := p.printR(prop.value, indent+8); err != nil {
+				return err
+			}
+			p.buf.WriteByte('\n')
+		}
+		p.printHashEnd(indent)
+	default:
+		s, err := p.r.ConvertToString(value)
+		if err != nil {
+			return err
+		}
+		p.buf.WriteString(s)
+	}
+	return nil
+}
+
+// printHashStart and printHashEnd write the parentheses around the elements of
+// an array or an object, like PHP's print_hash function.
+func (p *varPrinter) printHashStart(indent int) {
+	p.indent(indent)
+	p.buf.WriteString("(\n")
+}
+
+func (p *varPrinter) printHashEnd(indent int) {
+	p.indent(indent)
+	p.buf.WriteString(")\n")
+}
+
+// varExport is a ported function that works exactly the same as PHP's
+// php_var_export_ex function.
+func (p *varPrinter) varExport(value any, level int) error {
+	switch zvalTypeOf(value) {
+	case typeNull, typeResource:
+		p.buf.WriteString("NULL")
+	case typeBool:
+		fmt.Fprintf(&p.buf, "%t", value.(bool))
+	case typeLong:
+		l := longOf(value)
+		if l == math.MinInt64 && p.r.Version >= PHP80 {
+			// math.MinInt64 as a literal would be parsed as a float
+			p.buf.WriteString("-9223372036854775807-1")
+		} else {
+			p.buf.WriteString(strconv.FormatInt(l, 10))
+		}
+	case typeDouble:
+		p.buf.WriteString(formatDouble(doubleOf(value), p.r.ini().SerializePrecision, p.r.Version >= PHP70))
+	case typeString:
+		p.exportString(value.(string))
+	case typeArray:
+		if !p.enter(value) {
+			p.r.emit(EWarning, "var_export does not handle circular references")
+			p.buf.WriteString("NULL")
+			return nil
+		}
+		defer p.leave(value)
+
+		if level > 1 {
+			p.buf.WriteByte('\n')
+			p.indent(level - 1)
+		}
+		p.buf.WriteString("array (\n")
+		for _, e := range aggregateEntries(value) {
+			p.indent(level + 1)
+			if k, ok := e.key.(string); ok {
+				p.exportString(k)
+			} else {
+				fmt.Fprintf(&p.buf, "%v", e.key)
+			}
+			p.buf.WriteString(" => ")
+			if err := p.varExport(e.value, level+2); err != nil {
+				return err
+			}
+			p.buf.WriteString(",\n")
+		}
+		if level > 1 {
+			p.indent(level - 1)
+		}
+		p.buf.WriteString(")")
+	case typeObject:
+		if !p.enter(value) {
+			p.r.emit(EWarning, "var_export does not handle circular references")
+			p.buf.WriteString("NULL")
+			return nil
+		}
+		defer p.leave(value)
+
+		if level > 1 {
+			p.buf.WriteByte('\n')
+			p.indent(level - 1)
+		}
+		if p.r.Version >= PHP73 {
+			p.buf.WriteByte('\\')
+		}
+		p.buf.WriteString(className(value))
+		p.buf.WriteString("::__set_state(array(\n")
+		for _, prop := range objectProperties(value) {
+			p.indent(level + 2)
+			p.exportString(prop.name)
+			p.buf.WriteString(" => ")
+			if err := p.varExport(prop.value, level+2); err != nil {
+				return err
+			}
+			p.buf.WriteString(",\n")
+		}
+		if level > 1 {
+			p.indent(level - 1)
+		}
+		p.buf.WriteString("))")
+	default:
+		return &UnsupportedTypeError{value}
+	}
+	return nil
+}
+
+// exportString writes the given string as a single-quoted PHP string literal.
+// NUL bytes are written as "' . "\0" . '", since they can not be written in
+// single-quoted strings.
+func (p *varPrinter) exportString(s string) {
+	s = strings.NewReplacer(`\`, `\\`, `'`, `\'`, "\x00", `' . "\0" . '`).Replace(s)
+	p.buf.WriteByte('\'')
+	p.buf.WriteString(s)
+	p.buf.WriteByte('\'')
+}
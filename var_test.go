@@ -0,0 +1,260 @@
+package gophplib
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+func ExampleVarDump() {
+	VarDump(os.Stdout, ParseStr("a=1&b[]=foo&b[x]=bar"), 1.5, nil)
+	// Output:
+	// array(2) {
+	//   ["a"]=>
+	//   string(1) "1"
+	//   ["b"]=>
+	//   array(2) {
+	//     [0]=>
+	//     string(3) "foo"
+	//     ["x"]=>
+	//     string(3) "bar"
+	//   }
+	// }
+	// float(1.5)
+	// NULL
+}
+
+func ExamplePrintR() {
+	PrintR(os.Stdout, ParseStr("a=1&b[]=foo&b[x]=bar"))
+	// Output:
+	// Array
+	// (
+	//     [a] => 1
+	//     [b] => Array
+	//         (
+	//             [0] => foo
+	//             [x] => bar
+	//         )
+	//
+	// )
+}
+
+func ExampleVarExport() {
+	VarExport(os.Stdout, ParseStr("a=1&b=it's&c=1.5"))
+	// Output:
+	// array (
+	//   'a' => '1',
+	//   'b' => 'it\'s',
+	//   'c' => '1.5',
+	// )
+}
+
+func TestVarDump(t *testing.T) {
+	dog := &Dog{"choco", 5}
+	testCases := []struct {
+		version  Version
+		value    any
+		expected string
+	}{
+		{PHP56, true, "bool(true)\n"},
+		{PHP56, int8(-3), "int(-3)\n"},
+		{PHP56, 0.1, "float(0.1)\n"},
+		{PHP70, 0.1 + 0.2, "float(0.3)\n"},
+		{PHP71, 0.30000000000000004, "float(0.30000000000000004)\n"},
+		{PHP80, 1.0, "float(1)\n"},
+		{PHP80, -0.0 * -1, "float(0)\n"},
+		{PHP80, 1e100, "float(1.0E+100)\n"},
+		{PHP56, "한글", "string(6) \"한글\"\n"},
+		{PHP56, []int{}, "array(0) {\n}\n"},
+		{PHP56, map[string]any{"a": []any{nil}}, "array(1) {\n  [\"a\"]=>\n  array(1) {\n    [0]=>\n    NULL\n  }\n}\n"},
+		{PHP56, Point{1, 2}, "object(Point)#1 (2) {\n  [\"X\"]=>\n  int(1)\n  [\"Y\"]=>\n  int(2)\n}\n"},
+		{PHP80, []any{dog, dog, Dog{}}, `array(3) {
+  [0]=>
+  object(Dog)#1 (2) {
+    ["name":"Dog":private]=>
+    string(5) "choco"
+    ["age":"Dog":private]=>
+    int(5)
+  }
+  [1]=>
+  object(Dog)#1 (2) {
+    ["name":"Dog":private]=>
+    string(5) "choco"
+    ["age":"Dog":private]=>
+    int(5)
+  }
+  [2]=>
+  object(Dog)#2 (2) {
+    ["name":"Dog":private]=>
+    string(0) ""
+    ["age":"Dog":private]=>
+    int(0)
+  }
+}
+`},
+	}
+	for _, tc := range testCases {
+		var b strings.Builder
+		if err := NewRuntime(tc.version).VarDump(&b, tc.value); err != nil {
+			t.Errorf("VarDump(%v) on %d returned error %v", tc.value, tc.version, err)
+			continue
+		}
+		if b.String() != tc.expected {
+			t.Errorf("VarDump(%v) on %d = %q; want %q", tc.value, tc.version, b.String(), tc.expected)
+		}
+	}
+}
+
+func TestPrintR(t *testing.T) {
+	testCases := []struct {
+		version  Version
+		value    any
+		expected string
+	}{
+		{PHP56, nil, ""},
+		{PHP56, true, "1"},
+		{PHP56, 0.1 + 0.2, "0.3"},
+		{PHP80, 0.1 + 0.2, "0.3"},
+		{PHP56, []int{}, "Array\n(\n)\n"},
+		{PHP56, []any{[]int{1}}, "Array\n(\n    [0] => Array\n        (\n            [0] => 1\n        )\n\n)\n"},
+		{PHP56, &Dog{"choco", 5}, "Dog Object\n(\n    [name:Dog:private] => choco\n    [age:Dog:private] => 5\n)\n"},
+		{PHP56, Point{1, 2}, "Point Object\n(\n    [X] => 1\n    [Y] => 2\n)\n"},
+	}
+	for _, tc := range testCases {
+		var b strings.Builder
+		if err := NewRuntime(tc.version).PrintR(&b, tc.value); err != nil {
+			t.Errorf("PrintR(%v) on %d returned error %v", tc.value, tc.version, err)
+			continue
+		}
+		if b.String() != tc.expected {
+			t.Errorf("PrintR(%v) on %d = %q; want %q", tc.value, tc.version, b.String(), tc.expected)
+		}
+	}
+}
+
+func TestVarExport(t *testing.T) {
+	testCases := []struct {
+		version  Version
+		value    any
+		expected string
+	}{
+		{PHP56, nil, "NULL"},
+		{PHP56, false, "false"},
+		{PHP56, int64(-9223372036854775808), "-9223372036854775808"},
+		{PHP80, int64(-9223372036854775808), "-9223372036854775807-1"},
+		{PHP56, 1.0, "1"},
+		{PHP70, 1.0, "1.0"},
+		{PHP56, 0.1, "0.10000000000000001"},
+		{PHP71, 0.1, "0.1"},
+		{PHP80, 1e100, "1.0E+100"},
+		{PHP80, -1e100, "-1.0E+100"},
+		{PHP56, "a\\b'c\x00d", `'a\\b\'c' . "\0" . 'd'`},
+		{PHP56, getFile(), "NULL"},
+		{PHP56, []int{}, "array (\n)"},
+		{PHP56, Point{1, 2}, "Point::__set_state(array(\n   'X' => 1,\n   'Y' => 2,\n))"},
+		{PHP73, Point{1, 2}, "\\Point::__set_state(array(\n   'X' => 1,\n   'Y' => 2,\n))"},
+		{PHP80, []any{Point{1, 2}}, "array (\n  0 => \n  \\Point::__set_state(array(\n     'X' => 1,\n     'Y' => 2,\n  )),\n)"},
+	}
+	for _, tc := range testCases {
+		var b strings.Builder
+		if err := NewRuntime(tc.version).VarExport(&b, tc.value); err != nil {
+			t.Errorf("VarExport(%v) on %d returned error %v", tc.value, tc.version, err)
+			continue
+		}
+		if b.String() != tc.expected {
+			t.Errorf("VarExport(%v) on %d = %q; want %q", tc.value, tc.version, b.String(), tc.expected)
+		}
+	}
+}
+
+func TestVarRecursion(t *testing.T) {
+	arr := NewArray()
+	arr.Set("self", arr)
+
+	var b strings.Builder
+	VarDump(&b, arr)
+	if expected := "array(1) {\n  [\"self\"]=>\n  *RECURSION*\n}\n"; b.String() != expected {
+		t.Errorf("VarDump = %q; want %q", b.String(), expected)
+	}
+
+	b.Reset()
+	PrintR(&b, arr)
+	if expected := "Array\n(\n    [self] => Array\n *RECURSION*\n)\n"; b.String() != expected {
+		t.Errorf("PrintR = %q; want %q", b.String(), expected)
+	}
+
+	b.Reset()
+	var diags []Diagnostic
+	r := NewRuntime(PHP80).WithDiagnosticHandler(func(d Diagnostic) { diags = append(diags, d) })
+	r.VarExport(&b, arr)
+	if expected := "array (\n  'self' => NULL,\n)"; b.String() != expected {
+		t.Errorf("VarExport = %q; want %q", b.String(), expected)
+	}
+	if len(diags) != 1 || diags[0].Message != "var_export does not handle circular references" {
+		t.Errorf("VarExport emitted %v", diags)
+	}
+}
+
+func TestVarRecursionGoTypes(t *testing.T) {
+	m := map[string]any{}
+	m["self"] = m
+	s := []any{nil}
+	s[0] = s
+
+	testCases := []struct {
+		value   any
+		dump    string
+		printR  string
+		export  string
+		warning bool
+	}{
+		{m, "array(1) {\n  [\"self\"]=>\n  *RECURSION*\n}\n", "Array\n(\n    [self] => Array\n *RECURSION*\n)\n", "array (\n  'self' => NULL,\n)", true},
+		{s, "array(1) {\n  [0]=>\n  *RECURSION*\n}\n", "Array\n(\n    [0] => Array\n *RECURSION*\n)\n", "array (\n  0 => NULL,\n)", true},
+		// The same slices which are not nested are not recursion.
+		{[]any{s[:0], s[:0]}, "array(2) {\n  [0]=>\n  array(0) {\n  }\n  [1]=>\n  array(0) {\n  }\n}\n", "", "", false},
+	}
+	for _, tc := range testCases {
+		var b strings.Builder
+		VarDump(&b, tc.value)
+		if b.String() != tc.dump {
+			t.Errorf("VarDump = %q; want %q", b.String(), tc.dump)
+		}
+		if tc.printR == "" {
+			continue
+		}
+
+		b.Reset()
+		PrintR(&b, tc.value)
+		if b.String() != tc.printR {
+			t.Errorf("PrintR = %q; want %q", b.String(), tc.printR)
+		}
+
+		b.Reset()
+		var diags []Diagnostic
+		r := NewRuntime(PHP80).WithDiagnosticHandler(func(d Diagnostic) { diags = append(diags, d) })
+		r.VarExport(&b, tc.value)
+		if b.String() != tc.export {
+			t.Errorf("VarExport = %q; want %q", b.String(), tc.export)
+		}
+		if tc.warning != (len(diags) == 1) {
+			t.Errorf("VarExport emitted %v", diags)
+		}
+	}
+}
+
+func TestVarUnsupported(t *testing.T) {
+	var b strings.Builder
+	for _, err := range []error{
+		VarDump(&b, 1, []any{func() {}}),
+		PrintR(&b, []any{make(chan int)}),
+		VarExport(&b, []any{func() {}}),
+	} {
+		if !errors.Is(err, ErrUnsupportedType) {
+			t.Errorf("got error %v; want unsupported type", err)
+		}
+	}
+	if b.Len() != 0 {
+		t.Errorf("wrote %q; want nothing", b.String())
+	}
+}
@@ -2,37 +2,477 @@ package gophplib
 
 import (
 	"fmt"
+	"math"
 	"reflect"
+	"strings"
 )
 
-// zendParseArgAsString attempts to replicate the behavior of the 'zend_parse_arg_impl' function
-// from PHP 5.6, specifically for the case where the 'spec' parameter is "s".
-// It handles conversion of different types to string in a way that aligns with PHP's type juggling rules,
-// calling ConvertToString to manage string, int, float, and bool types, akin to PHP's _convert_to_string.
+// Function describes a PHP function for ParseParameters.
+type Function struct {
+	// Name is the name of the function. (ex: "strlen")
+	Name string
+
+	// Params is the names of the parameters without the leading '$'. It is
+	// used in the error messages of PHP 8. (ex: []string{"string"})
+	Params []string
+}
+
+// ParseParameters is a ported function that works exactly the same as PHP's
+// zend_parse_parameters function. It checks the number and the types of args
+// against spec, converts them like PHP does for internal functions in
+// non-strict mode, and stores the converted values to dest, in order.
+//
+// Each character of spec specifies the type of a parameter, and dest must be
+// a pointer to the Go type of each parameter as follows:
+//   - s: string (*string)
+//   - p: string without NUL bytes, for file paths (*string)
+//   - l: integer (*int64)
+//   - d: float (*float64)
+//   - b: bool (*bool)
+//   - a: array, stored as given (*any)
+//   - h: array, converted to Array (**Array)
+//   - r: resource, stored as given (*any)
+//   - z: any value, stored as given (*any)
+//
+// The special characters of spec are as follows:
+//   - !: The preceding parameter is nullable. For s, p, l, d and b, dest must
+//     be a pointer to a pointer, such as **string, and nil is stored if the
+//     argument is nil. For a, h, r and z, nil is stored as is.
+//   - |: The following parameters are optional. dest of the optional
+//     parameters which are not given are left untouched.
 //
-// This function returns error if given argument is not one of following:
-// string, int, int8, int16, int32, int64, float32, float64, bool, nil
-// and any type which does not implement interface { toString() string }.
+// If args do not match spec, it returns either *TypeError, *ArgumentCountError
+// or *ValueError, whose message is the same as what PHP reports. Before PHP 8,
+// it is the message of the warning PHP emits before returning NULL, such as
+// "strlen() expects parameter 1 to be string, array given". Since PHP 8, it is
+// the message of the thrown TypeError, such as "strlen(): Argument #1
+// ($string) must be of type string, array given". The warning is also emitted
+// to the diagnostic handler before PHP 8. Callers should return the zero value
+// of their results with the error, like PHP functions return NULL in this
+// case.
+//
+// If spec is malformed, or dest does not match spec, it returns *Error, which
+// indicates a bug of the caller rather than invalid arguments.
+//
+// References:
+//   - https://github.com/php/php-src/blob/php-5.6.40/Zend/zend_API.c
+//   - https://github.com/php/php-src/blob/php-7.4.0/Zend/zend_API.c
+//   - https://github.com/php/php-src/blob/php-8.3.0/Zend/zend_API.c
+func (r *Runtime) ParseParameters(fn Function, args []any, spec string, dest ...any) error {
+	type param struct {
+		spec     byte
+		nullable bool
+	}
+
+	// Parse spec
+	var params []param
+	minArgs := -1
+	for i := 0; i < len(spec); i++ {
+		switch c := spec[i]; c {
+		case 's', 'p', 'l', 'd', 'b', 'a', 'h', 'r', 'z':
+			params = append(params, param{spec: c})
+		case '!':
+			if len(params) == 0 {
+				return &Error{Message: fmt.Sprintf("%s(): bad type specifier while parsing parameters", fn.Name)}
+			}
+			params[len(params)-1].nullable = true
+		case '|':
+			if minArgs != -1 {
+				return &Error{Message: fmt.Sprintf("%s(): bad type specifier while parsing parameters", fn.Name)}
+			}
+			minArgs = len(params)
+		default:
+			return &Error{Message: fmt.Sprintf("%s(): bad type specifier while parsing parameters", fn.Name)}
+		}
+	}
+	maxArgs := len(params)
+	if minArgs == -1 {
+		minArgs = maxArgs
+	}
+	if len(dest) != maxArgs {
+		return &Error{Message: fmt.Sprintf("%s(): could not obtain parameters for parsing", fn.Name)}
+	}
+
+	// Check the number of arguments
+	if len(args) < minArgs || len(args) > maxArgs {
+		return r.wrongParametersCountError(fn, len(args), minArgs, maxArgs)
+	}
+
+	for i, arg := range args {
+		if zvalTypeOf(arg) == typeUnsupported {
+			return &UnsupportedTypeError{arg}
+		}
+		p := params[i]
+		if arg == nil && !p.nullable && r.Version >= PHP81 {
+			if typ, ok := scalarTypeNames[p.spec]; ok {
+				r.emit(EDeprecated, "%s(): Passing null to parameter #%d%s of type %s is deprecated", fn.Name, i+1, fn.paramName(i), typ)
+			}
+		}
+		value, ok := r.parseArg(arg, p.spec, p.nullable)
+		if !ok {
+			return r.wrongParameterTypeError(fn, i, p.spec, p.nullable, arg)
+		}
+		if p.spec == 'p' && value != nil && strings.IndexByte(value.(string), 0) != -1 {
+			if r.Version >= PHP80 {
+				return &ValueError{
+					Function:  fn.Name,
+					Param:     i + 1,
+					ParamName: fn.param(i),
+					Message:   fmt.Sprintf("%s(): Argument #%d%s must not contain any null bytes", fn.Name, i+1, fn.paramName(i)),
+				}
+			}
+			return r.wrongParameterTypeError(fn, i, p.spec, p.nullable, arg)
+		}
+		if !storeArg(dest[i], value, p.spec, p.nullable) {
+			return &Error{Message: fmt.Sprintf("%s(): invalid destination %T for parameter %d", fn.Name, dest[i], i+1)}
+		}
+	}
+	return nil
+}
+
+// scalarTypeNames is the PHP 8 type names of the scalar type specs.
+var scalarTypeNames = map[byte]string{'s': "string", 'p': "string", 'l': "int", 'd': "float", 'b': "bool"}
+
+// parseArg converts the given argument to the Go type of the given spec like
+// PHP's zend_parse_arg_impl function. It returns false if the argument can not
+// be converted.
+func (r *Runtime) parseArg(arg any, spec byte, nullable bool) (any, bool) {
+	if arg == nil && (nullable || spec == 'z') {
+		return nil, true
+	}
+
+	switch spec {
+	case 's', 'p':
+		return r.parseArgString(arg)
+	case 'l':
+		return r.parseArgLong(arg)
+	case 'd':
+		return r.parseArgDouble(arg)
+	case 'b':
+		switch zvalTypeOf(arg) {
+		case typeNull, typeBool, typeLong, typeDouble, typeString:
+			return r.ConvertToBool(arg), true
+		}
+		return nil, false
+	case 'a':
+		return arg, zvalTypeOf(arg) == typeArray
+	case 'h':
+		if zvalTypeOf(arg) != typeArray {
+			return nil, false
+		}
+		arr, err := NewArrayFrom(arg)
+		return arr, err == nil
+	case 'r':
+		return arg, zvalTypeOf(arg) == typeResource
+	default:
+		return arg, true
+	}
+}
+
+// parseArgString converts the given argument to string like PHP's
+// zend_parse_arg_str_weak function.
+func (r *Runtime) parseArgString(arg any) (string, bool) {
+	switch zvalTypeOf(arg) {
+	case typeNull, typeBool, typeLong, typeDouble, typeString:
+		str, err := r.ConvertToString(arg)
+		return str, err == nil
+	case typeObject:
+		// For types implementing Stringable, get the value of ToString()
+		if s, ok := asStringable(arg); ok {
+			return s.ToString(), true
+		}
+	}
+	return "", false
+}
+
+// parseArgLong converts the given argument to int64 like PHP's
+// zend_parse_arg_long_weak function. PHP 5.6 wraps floats out of range of
+// int64 around, while PHP 7 and later reject them.
+func (r *Runtime) parseArgLong(arg any) (int64, bool) {
+	switch zvalTypeOf(arg) {
+	case typeNull:
+		return 0, true
+	case typeBool:
+		return int64(boolToInt(arg.(bool))), true
+	case typeLong:
+		return longOf(arg), true
+	case typeDouble:
+		d := doubleOf(arg)
+		l, ok := r.doubleArgToLong(d)
+		if ok && r.Version >= PHP81 && float64(l) != d {
+			r.emit(EDeprecated, "Implicit conversion from float %s to int loses precision", smartStrAppendDouble(nil, d, -1, false))
+		}
+		return l, ok
+	case typeString:
+		ns := r.numericArg(arg.(string))
+		switch ns.Type {
+		case NumericLong:
+			return ns.Long, true
+		case NumericDouble:
+			l, ok := r.doubleArgToLong(ns.Double)
+			if ok && r.Version >= PHP81 && float64(l) != ns.Double {
+				r.emit(EDeprecated, "Implicit conversion from float-string \"%s\" to int loses precision", arg)
+			}
+			return l, ok
+		}
+	}
+	return 0, false
+}
+
+// numericArg parses a string argument as a numeric string like PHP's
+// is_numeric_str_function, and emits the diagnostic for leading numeric
+// strings like "1abc".
+func (r *Runtime) numericArg(s string) NumericString {
+	ns := r.IsNumericString(s, AllowErrorsWithNotice)
+	if ns.TrailingData && r.Version >= PHP80 {
+		r.emit(EWarning, "A non-numeric value encountered")
+	}
+	return ns
+}
+
+// doubleArgToLong converts a float argument to int64 for parseArgLong.
+func (r *Runtime) doubleArgToLong(d float64) (int64, bool) {
+	if r.Version >= PHP70 && (math.IsNaN(d) || d >= twoPow63 || d < -twoPow63) {
+		return 0, false
+	}
+	return r.dvalToLval(d), true
+}
+
+// parseArgDouble converts the given argument to float64 like PHP's
+// zend_parse_arg_double_weak function.
+func (r *Runtime) parseArgDouble(arg any) (float64, bool) {
+	switch zvalTypeOf(arg) {
+	case typeNull, typeBool, typeLong, typeDouble:
+		d, err := r.ConvertToDouble(arg)
+		return d, err == nil
+	case typeString:
+		ns := r.numericArg(arg.(string))
+		switch ns.Type {
+		case NumericLong:
+			return float64(ns.Long), true
+		case NumericDouble:
+			return ns.Double, true
+		}
+	}
+	return 0, false
+}
+
+// storeArg stores the parsed value to dest. It returns false if the type of
+// dest does not match the spec.
+func storeArg(dest, value any, spec byte, nullable bool) bool {
+	switch spec {
+	case 's', 'p':
+		return storeScalarArg[string](dest, value, nullable)
+	case 'l':
+		return storeScalarArg[int64](dest, value, nullable)
+	case 'd':
+		return storeScalarArg[float64](dest, value, nullable)
+	case 'b':
+		return storeScalarArg[bool](dest, value, nullable)
+	case 'h':
+		d, ok := dest.(**Array)
+		if ok {
+			*d, _ = value.(*Array)
+		}
+		return ok
+	default:
+		d, ok := dest.(*any)
+		if ok {
+			*d = value
+		}
+		return ok
+	}
+}
+
+// storeScalarArg stores the parsed value to dest, which is either *T, or **T
+// if the parameter is nullable.
+func storeScalarArg[T any](dest, value any, nullable bool) bool {
+	if !nullable {
+		d, ok := dest.(*T)
+		if ok {
+			*d = value.(T)
+		}
+		return ok
+	}
+	d, ok := dest.(**T)
+	if ok {
+		if value == nil {
+			*d = nil
+		} else {
+			v := value.(T)
+			*d = &v
+		}
+	}
+	return ok
+}
+
+// param returns the name of i-th parameter, or an empty string if unknown.
+func (fn Function) param(i int) string {
+	if i >= len(fn.Params) {
+		return ""
+	}
+	return fn.Params[i]
+}
+
+// paramName returns the name of i-th parameter formatted for the error
+// messages of PHP 8, such as " ($string)". It returns an empty string if the
+// name is unknown.
+func (fn Function) paramName(i int) string {
+	if fn.param(i) == "" {
+		return ""
+	}
+	return fmt.Sprintf(" ($%s)", fn.Params[i])
+}
+
+// wrongParametersCountError returns the error of zend_wrong_parameters_count_error.
+func (r *Runtime) wrongParametersCountError(fn Function, numArgs, minArgs, maxArgs int) error {
+	var limit string
+	expected := maxArgs
+	switch {
+	case minArgs == maxArgs:
+		limit = "exactly"
+	case numArgs < minArgs:
+		limit = "at least"
+		expected = minArgs
+	default:
+		limit = "at most"
+	}
+
+	noun := "parameter"
+	if r.Version >= PHP80 {
+		noun = "argument"
+	}
+	if expected != 1 {
+		noun += "s"
+	}
+	err := &ArgumentCountError{
+		Function: fn.Name,
+		Min:      minArgs,
+		Max:      maxArgs,
+		Given:    numArgs,
+		Message:  fmt.Sprintf("%s() expects %s %d %s, %d given", fn.Name, limit, expected, noun, numArgs),
+	}
+	if r.Version >= PHP80 {
+		return err
+	}
+	return r.warning(err)
+}
+
+// wrongParameterTypeError returns the error of zend_wrong_parameter_type_error.
+func (r *Runtime) wrongParameterTypeError(fn Function, i int, spec byte, nullable bool, arg any) error {
+	err := &TypeError{
+		Function:  fn.Name,
+		Param:     i + 1,
+		ParamName: fn.param(i),
+		Given:     zendZvalTypeName(arg),
+	}
+	var ok bool
+	if err.Expected, ok = scalarTypeNames[spec]; !ok {
+		err.Expected = "array"
+		if spec == 'r' {
+			err.Expected = "resource"
+		}
+	}
+	if nullable {
+		err.Expected = "?" + err.Expected
+	}
+	if r.Version >= PHP80 {
+		err.Message = fmt.Sprintf("%s(): Argument #%d%s must be of type %s, %s given", fn.Name, i+1, fn.paramName(i), err.Expected, err.Given)
+		return err
+	}
+
+	var expected string
+	switch spec {
+	case 's':
+		expected = "string"
+	case 'p':
+		expected = "a valid path"
+	case 'a', 'h':
+		expected = "array"
+	case 'r':
+		expected = "resource"
+	case 'l':
+		expected = "int"
+		if r.Version < PHP70 {
+			expected = "long"
+		}
+	case 'd':
+		expected = "float"
+		if r.Version < PHP70 {
+			expected = "double"
+		}
+	case 'b':
+		expected = "bool"
+		if r.Version < PHP70 {
+			expected = "boolean"
+		}
+	}
+	err.Message = fmt.Sprintf("%s() expects parameter %d to be %s, %s given", fn.Name, i+1, expected, r.zvalTypeName(arg))
+	return r.warning(err)
+}
+
+// zvalTypeName returns the PHP type name of the given value used in the error
+// messages of the emulated PHP version, like zend_zval_type_name function.
+// PHP 8 uses the class name for objects, and older versions use "object".
+//
+// References:
+//   - https://github.com/php/php-src/blob/php-5.6.40/Zend/zend_API.c
+//   - https://github.com/php/php-src/blob/php-7.4.0/Zend/zend_API.c
+func (r *Runtime) zvalTypeName(value any) string {
+	if r.Version >= PHP80 {
+		return zendZvalTypeName(value)
+	}
+	switch zvalTypeOf(value) {
+	case typeNull:
+		return "null"
+	case typeBool:
+		if r.Version < PHP70 {
+			return "boolean"
+		}
+		return "bool"
+	case typeLong:
+		if r.Version < PHP70 {
+			return "integer"
+		}
+		return "int"
+	case typeDouble:
+		if r.Version < PHP70 {
+			return "double"
+		}
+		return "float"
+	case typeObject:
+		return "object"
+	default:
+		return zendZvalTypeName(value)
+	}
+}
+
+// zendZvalTypeName is a ported function that works exactly the same as PHP 8's
+// zend_zval_type_name function, except that it returns the class name for
+// objects, like zend_zval_value_name does. It is used in PHP 8's error
+// messages like "Unsupported operand types: array + int".
 //
-// Reference :
-//   - https://github.com/php/php-src/blob/php-5.6.40/Zend/zend_API.c#L685-L713
-//   - https://github.com/php/php-src/blob/php-5.6.40/Zend/zend_API.c#L425-L470
-//   - https://github.com/php/php-src/blob/php-5.6.40/Zend/zend_operators.c#L593-L661
-//   - https://github.com/php/php-src/blob/php-5.6.40/Zend/zend_API.c#L261-L301
-func zendParseArgAsString(value any) (string, error) {
-	var str string
-
-	switch v := value.(type) {
-	case string, int, int8, int16, int32, int64, float32, float64, bool:
-		return ConvertToString(value)
-	case nil:
-		// TODO: handle check_null
-		str = ""
-	case toStringAble:
-		// For types implementing toString(), get the value of toString()
-		str = v.toString()
+// Reference:
+//   - https://github.com/php/php-src/blob/php-8.3.0/Zend/zend_API.c
+func zendZvalTypeName(value any) string {
+	switch zvalTypeOf(value) {
+	case typeNull:
+		return "null"
+	case typeBool:
+		return "bool"
+	case typeLong:
+		return "int"
+	case typeDouble:
+		return "float"
+	case typeString:
+		return "string"
+	case typeArray:
+		return "array"
+	case typeObject:
+		return className(value)
+	case typeResource:
+		return "resource"
 	default:
-		return "", fmt.Errorf("unsupported type : %s", reflect.TypeOf(v))
+		return reflect.TypeOf(value).String()
 	}
-	return str, nil
 }
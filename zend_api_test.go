@@ -1,14 +1,35 @@
 package gophplib
 
 import (
+	"errors"
 	"fmt"
 	"math"
 	"reflect"
 	"testing"
 )
 
-func TestZendParseArgAsString(t *testing.T) {
+func ExampleRuntime_ParseParameters() {
+	strRepeat := Function{"str_repeat", []string{"string", "times"}}
+	args := []any{"ab", "3"}
 
+	var str string
+	var times int64
+	fmt.Println(NewRuntime(PHP56).ParseParameters(strRepeat, args, "sl", &str, &times), str, times)
+
+	args = []any{"ab", []int{}}
+	fmt.Println(NewRuntime(PHP56).ParseParameters(strRepeat, args, "sl", &str, &times))
+	fmt.Println(NewRuntime(PHP74).ParseParameters(strRepeat, args, "sl", &str, &times))
+	fmt.Println(NewRuntime(PHP80).ParseParameters(strRepeat, args, "sl", &str, &times))
+	fmt.Println(NewRuntime(PHP80).ParseParameters(strRepeat, args[:1], "sl", &str, &times))
+	// Output:
+	// <nil> ab 3
+	// str_repeat() expects parameter 2 to be long, array given
+	// str_repeat() expects parameter 2 to be int, array given
+	// str_repeat(): Argument #2 ($times) must be of type int, array given
+	// str_repeat() expects exactly 2 arguments, 1 given
+}
+
+func TestParseParametersString(t *testing.T) {
 	testCase := []struct {
 		any
 		string
@@ -32,27 +53,238 @@ func TestZendParseArgAsString(t *testing.T) {
 		{false, ""},
 		{nil, ""},
 		{Sample{}, "sample object"},
-		{Sample2{}, ""},
-		{[]int{1, 2, 3}, ""},
-		{[]string{"hello", "world"}, ""},
-		{[]interface{}{[]interface{}{1, 2}, []interface{}{"a", "b"}}, ""},
-		{getFile(), ""},
-		{CustomType{"Hello world"}, ""},
+		{&Sample{}, "sample object"},
+		{Bird{name: "tweety"}, "bird named tweety"},
+		{&Bird{name: "tweety"}, "bird named tweety"},
+		{FromStringer(Color{name: "red"}), "color red"},
 	}
 	for _, tc := range testCase {
 		testName := fmt.Sprintf("%v", tc.any)
 		t.Run(testName, func(t *testing.T) {
-			result, err := zendParseArgAsString(tc.any)
+			var result string
+			err := defaultRuntime.ParseParameters(Function{Name: "f"}, []any{tc.any}, "s", &result)
 			if err != nil {
-				expectedErr := fmt.Errorf("unsupported type : %s", reflect.TypeOf(tc.any))
-				if err.Error() != expectedErr.Error() {
-					t.Errorf("%s: expected error : %s, got %s", testName, expectedErr, err)
+				t.Errorf("%s: expected %v, got error %v", testName, tc.string, err)
+			} else if !reflect.DeepEqual(result, tc.string) {
+				t.Errorf("%s: expected %v, got %v", testName, tc.string, result)
+			}
+		})
+	}
+
+	errorCases := []struct {
+		value any
+		php56 string
+		php80 string
+	}{
+		{Sample2{}, "f() expects parameter 1 to be string, object given", "f(): Argument #1 ($str) must be of type string, Sample2 given"},
+		{Color{name: "red"}, "f() expects parameter 1 to be string, object given", "f(): Argument #1 ($str) must be of type string, Color given"},
+		{CustomType{"Hello world"}, "f() expects parameter 1 to be string, object given", "f(): Argument #1 ($str) must be of type string, CustomType given"},
+		{[]int{1, 2, 3}, "f() expects parameter 1 to be string, array given", "f(): Argument #1 ($str) must be of type string, array given"},
+		{getFile(), "f() expects parameter 1 to be string, resource given", "f(): Argument #1 ($str) must be of type string, resource given"},
+		{func() {}, "unsupported type : func()", "unsupported type : func()"},
+	}
+	for _, tc := range errorCases {
+		for _, v := range []struct {
+			version  Version
+			expected string
+		}{{PHP56, tc.php56}, {PHP80, tc.php80}} {
+			var result string
+			err := NewRuntime(v.version).ParseParameters(Function{"f", []string{"str"}}, []any{tc.value}, "s", &result)
+			if err == nil || err.Error() != v.expected {
+				t.Errorf("ParseParameters(%T) on %d returned error %v; want %q", tc.value, v.version, err, v.expected)
+			}
+		}
+	}
+}
+
+// TestParseParameters tests type specs of ParseParameters.
+//
+// References:
+//   - https://wiki.php.net/rfc/scalar_type_hints_v5
+func TestParseParameters(t *testing.T) {
+	testCases := []struct {
+		spec     string
+		arg      any
+		expected any
+		php56    string
+		php74    string
+		php80    string
+	}{
+		{"l", "12", int64(12), "", "", ""},
+		{"l", " 12abc", int64(12), "", "", ""},
+		{"l", 1.9, int64(1), "", "", ""},
+		{"l", "1e3", int64(1000), "", "", ""},
+		{"l", true, int64(1), "", "", ""},
+		{"l", nil, int64(0), "", "", ""},
+		{"l", "abc", nil,
+			"f() expects parameter 1 to be long, string given",
+			"f() expects parameter 1 to be int, string given",
+			"f(): Argument #1 ($x) must be of type int, string given"},
+		{"l", 1e20, nil,
+			"",
+			"f() expects parameter 1 to be int, float given",
+			"f(): Argument #1 ($x) must be of type int, float given"},
+		{"l", math.NaN(), nil,
+			"",
+			"f() expects parameter 1 to be int, float given",
+			"f(): Argument #1 ($x) must be of type int, float given"},
+		{"l", []int{}, nil,
+			"f() expects parameter 1 to be long, array given",
+			"f() expects parameter 1 to be int, array given",
+			"f(): Argument #1 ($x) must be of type int, array given"},
+		{"d", "1.5", 1.5, "", "", ""},
+		{"d", 3, 3.0, "", "", ""},
+		{"d", false, 0.0, "", "", ""},
+		{"d", "x", nil,
+			"f() expects parameter 1 to be double, string given",
+			"f() expects parameter 1 to be float, string given",
+			"f(): Argument #1 ($x) must be of type float, string given"},
+		{"b", "0", false, "", "", ""},
+		{"b", 0.5, true, "", "", ""},
+		{"b", nil, false, "", "", ""},
+		{"b", Point{}, nil,
+			"f() expects parameter 1 to be boolean, object given",
+			"f() expects parameter 1 to be bool, object given",
+			"f(): Argument #1 ($x) must be of type bool, Point given"},
+		{"p", "/tmp", "/tmp", "", "", ""},
+		{"p", "/tmp\x00.php", nil,
+			"f() expects parameter 1 to be a valid path, string given",
+			"f() expects parameter 1 to be a valid path, string given",
+			"f(): Argument #1 ($x) must not contain any null bytes"},
+		{"p", []int{}, nil,
+			"f() expects parameter 1 to be a valid path, array given",
+			"f() expects parameter 1 to be a valid path, array given",
+			"f(): Argument #1 ($x) must be of type string, array given"},
+		{"a", []int{1}, []int{1}, "", "", ""},
+		{"a", "abc", nil,
+			"f() expects parameter 1 to be array, string given",
+			"f() expects parameter 1 to be array, string given",
+			"f(): Argument #1 ($x) must be of type array, string given"},
+		{"a!", nil, nil, "", "", ""},
+		{"a!", 1, nil,
+			"f() expects parameter 1 to be array, integer given",
+			"f() expects parameter 1 to be array, int given",
+			"f(): Argument #1 ($x) must be of type ?array, int given"},
+		{"z", nil, nil, "", "", ""},
+		{"z", Point{1, 2}, Point{1, 2}, "", "", ""},
+	}
+
+	for _, tc := range testCases {
+		for _, v := range []struct {
+			version  Version
+			expected string
+		}{{PHP56, tc.php56}, {PHP74, tc.php74}, {PHP80, tc.php80}} {
+			var dest any
+			switch tc.spec {
+			case "l":
+				dest = new(int64)
+			case "d":
+				dest = new(float64)
+			case "b":
+				dest = new(bool)
+			case "p":
+				dest = new(string)
+			default:
+				dest = new(any)
+			}
+
+			err := NewRuntime(v.version).ParseParameters(Function{"f", []string{"x"}}, []any{tc.arg}, tc.spec, dest)
+			if v.expected != "" {
+				if err == nil || err.Error() != v.expected {
+					t.Errorf("ParseParameters(%q, %#v) on %d returned error %v; want %q", tc.spec, tc.arg, v.version, err, v.expected)
 				}
-			} else {
-				if !reflect.DeepEqual(result, tc.string) {
-					t.Errorf("%s: expected %v, got %v", testName, tc.string, result)
+				continue
+			}
+			if err != nil {
+				t.Errorf("ParseParameters(%q, %#v) on %d returned error %v", tc.spec, tc.arg, v.version, err)
+				continue
+			}
+			if tc.expected != nil {
+				if result := reflect.ValueOf(dest).Elem().Interface(); !reflect.DeepEqual(result, tc.expected) {
+					t.Errorf("ParseParameters(%q, %#v) on %d stored %#v; want %#v", tc.spec, tc.arg, v.version, result, tc.expected)
 				}
 			}
-		})
+		}
+	}
+}
+
+func TestParseParametersNullable(t *testing.T) {
+	fn := Function{"f", []string{"s", "l", "h"}}
+
+	s, l, h := new(string), new(int64), NewArray()
+	if err := defaultRuntime.ParseParameters(fn, []any{nil, nil, nil}, "s!l!h!", &s, &l, &h); err != nil {
+		t.Fatalf("ParseParameters returned error %v", err)
+	}
+	if s != nil || l != nil || h != nil {
+		t.Errorf("ParseParameters stored %v, %v, %v; want nil", s, l, h)
+	}
+
+	if err := defaultRuntime.ParseParameters(fn, []any{"a", "12", map[string]int{"b": 1}}, "s!l!h!", &s, &l, &h); err != nil {
+		t.Fatalf("ParseParameters returned error %v", err)
+	}
+	if s == nil || *s != "a" || l == nil || *l != 12 || !Identical(h, omap("b", 1)) {
+		t.Errorf("ParseParameters stored %v, %v, %v", s, l, h)
+	}
+}
+
+func TestParseParametersCount(t *testing.T) {
+	testCases := []struct {
+		spec  string
+		args  []any
+		php56 string
+		php80 string
+	}{
+		{"s", []any{}, "f() expects exactly 1 parameter, 0 given", "f() expects exactly 1 argument, 0 given"},
+		{"ss", []any{"a", "b", "c"}, "f() expects exactly 2 parameters, 3 given", "f() expects exactly 2 arguments, 3 given"},
+		{"s|l", []any{}, "f() expects at least 1 parameter, 0 given", "f() expects at least 1 argument, 0 given"},
+		{"s|l", []any{"a", 1, 2}, "f() expects at most 2 parameters, 3 given", "f() expects at most 2 arguments, 3 given"},
+		{"|z", []any{1, 2}, "f() expects at most 1 parameter, 2 given", "f() expects at most 1 argument, 2 given"},
+	}
+
+	for _, tc := range testCases {
+		var s string
+		var l int64
+		var z any
+		dest := map[string][]any{"s": {&s}, "ss": {&s, &s}, "s|l": {&s, &l}, "|z": {&z}}[tc.spec]
+		for _, v := range []struct {
+			version  Version
+			expected string
+		}{{PHP56, tc.php56}, {PHP80, tc.php80}} {
+			err := NewRuntime(v.version).ParseParameters(Function{Name: "f"}, tc.args, tc.spec, dest...)
+			if err == nil || err.Error() != v.expected {
+				t.Errorf("ParseParameters(%q, %v) on %d returned error %v; want %q", tc.spec, tc.args, v.version, err, v.expected)
+			}
+		}
+	}
+
+	s, l := "untouched", int64(-1)
+	if err := defaultRuntime.ParseParameters(Function{Name: "f"}, []any{"a"}, "s|l", &s, &l); err != nil || s != "a" || l != -1 {
+		t.Errorf("ParseParameters stored %q, %d, returned error %v", s, l, err)
+	}
+}
+
+func TestParseParametersInvalid(t *testing.T) {
+	var s string
+	var l int64
+	testCases := []struct {
+		spec string
+		arg  any
+		dest []any
+		err  string
+	}{
+		{"x", "1", []any{&s}, "f(): bad type specifier while parsing parameters"},
+		{"!s", "1", []any{&s}, "f(): bad type specifier while parsing parameters"},
+		{"s||s", "1", []any{&s, &s}, "f(): bad type specifier while parsing parameters"},
+		{"ss", "1", []any{&s}, "f(): could not obtain parameters for parsing"},
+		{"l", "1", []any{&s}, "f(): invalid destination *string for parameter 1"},
+		{"s!", "1", []any{&s}, "f(): invalid destination *string for parameter 1"},
+		{"h", []int{}, []any{&l}, "f(): invalid destination *int64 for parameter 1"},
+	}
+	for _, tc := range testCases {
+		err := defaultRuntime.ParseParameters(Function{Name: "f"}, []any{tc.arg}, tc.spec, tc.dest...)
+		var e *Error
+		if !errors.As(err, &e) || !errors.Is(err, ErrError) || err.Error() != tc.err {
+			t.Errorf("ParseParameters(%q) = %v; want *Error %q", tc.spec, err, tc.err)
+		}
 	}
 }
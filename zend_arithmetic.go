@@ -0,0 +1,490 @@
+package gophplib
+
+import (
+	"fmt"
+	"math"
+)
+
+// errDivisionByZero is returned by Div on PHP 8, which throws DivisionByZeroError.
+var errDivisionByZero error = &DivisionByZeroError{Message: "Division by zero"}
+
+// errModuloByZero is returned by Mod on PHP 7 and later, which throw
+// DivisionByZeroError.
+var errModuloByZero error = &DivisionByZeroError{Message: "Modulo by zero"}
+
+// Add is a ported function that works exactly the same as PHP's add_function, which implements the + operator.
+// For more information, see the [official PHP documentation].
+//
+// Operands are converted to numbers in the following way, and the result is either int64 or float64:
+//   - Integers are int64, and floats are float64. Integer overflow results in float64.
+//   - nil is 0, and bool is 0 or 1.
+//   - Numeric strings are converted to the number they represent, and leading-numeric strings like "123abc"
+//     to their leading number. Other strings are 0 before PHP 8, and are unsupported operands since PHP 8.
+//   - Resources are converted to their IDs.
+//   - Objects are 1 before PHP 8, and are unsupported operands since PHP 8.
+//
+// If both operands are arrays, it returns their union as *Array, which has all elements of a and the
+// elements of b whose keys do not exist in a. Any other operation on arrays is unsupported.
+//
+// This function returns error with message "Unsupported operand types" if any operand is unsupported. Since
+// PHP 8, the message includes the types of the operands, e.g. "Unsupported operand types: array + int". It
+// also returns error if any of the given values is not supported by this package. See LooseEquals for the
+// list of supported types.
+//
+// Reference:
+//   - https://github.com/php/php-src/blob/php-5.6.40/Zend/zend_operators.c
+//   - https://github.com/php/php-src/blob/php-8.3.0/Zend/zend_operators.c
+//   - https://wiki.php.net/rfc/saner-string-to-number
+//
+// [official PHP documentation]: https://www.php.net/manual/en/language.operators.arithmetic.php
+func (r *Runtime) Add(a, b any) (any, error) {
+	if err := checkOperands(a, b); err != nil {
+		return nil, err
+	}
+	if zvalTypeOf(a) == typeArray && zvalTypeOf(b) == typeArray {
+		return arrayUnion(a, b)
+	}
+
+	n1, n2, err := r.operandsToNumbers(a, b, "+")
+	if err != nil {
+		return nil, err
+	}
+	if l1, l2, ok := bothLong(n1, n2); ok {
+		sum := l1 + l2
+		if (l1 >= 0) == (l2 >= 0) && (sum >= 0) != (l1 >= 0) {
+			// integer overflow
+			return float64(l1) + float64(l2), nil
+		}
+		return sum, nil
+	}
+	return toDouble(n1) + toDouble(n2), nil
+}
+
+// Add works the same as Runtime.Add of a Runtime emulating PHP 5.6.
+func Add(a, b any) (any, error) {
+	return defaultRuntime.Add(a, b)
+}
+
+// Sub is a ported function that works exactly the same as PHP's sub_function, which implements the - operator.
+// Operands are converted in the same way as Add, but arrays are always unsupported.
+//
+// Reference:
+//   - https://github.com/php/php-src/blob/php-5.6.40/Zend/zend_operators.c
+//   - https://github.com/php/php-src/blob/php-8.3.0/Zend/zend_operators.c
+func (r *Runtime) Sub(a, b any) (any, error) {
+	if err := checkOperands(a, b); err != nil {
+		return nil, err
+	}
+	n1, n2, err := r.operandsToNumbers(a, b, "-")
+	if err != nil {
+		return nil, err
+	}
+	if l1, l2, ok := bothLong(n1, n2); ok {
+		diff := l1 - l2
+		if (l1 >= 0) != (l2 >= 0) && (diff >= 0) != (l1 >= 0) {
+			// integer overflow
+			return float64(l1) - float64(l2), nil
+		}
+		return diff, nil
+	}
+	return toDouble(n1) - toDouble(n2), nil
+}
+
+// Sub works the same as Runtime.Sub of a Runtime emulating PHP 5.6.
+func Sub(a, b any) (any, error) {
+	return defaultRuntime.Sub(a, b)
+}
+
+// Mul is a ported function that works exactly the same as PHP's mul_function, which implements the * operator.
+// Operands are converted in the same way as Add, but arrays are always unsupported.
+//
+// Reference:
+//   - https://github.com/php/php-src/blob/php-5.6.40/Zend/zend_operators.c
+//   - https://github.com/php/php-src/blob/php-8.3.0/Zend/zend_operators.c
+func (r *Runtime) Mul(a, b any) (any, error) {
+	if err := checkOperands(a, b); err != nil {
+		return nil, err
+	}
+	n1, n2, err := r.operandsToNumbers(a, b, "*")
+	if err != nil {
+		return nil, err
+	}
+	if l1, l2, ok := bothLong(n1, n2); ok {
+		l, d, overflow := signedMultiplyLong(l1, l2)
+		if overflow {
+			return d, nil
+		}
+		return l, nil
+	}
+	return toDouble(n1) * toDouble(n2), nil
+}
+
+// Mul works the same as Runtime.Mul of a Runtime emulating PHP 5.6.
+func Mul(a, b any) (any, error) {
+	return defaultRuntime.Mul(a, b)
+}
+
+// Div is a ported function that works exactly the same as PHP's div_function, which implements the / operator.
+// Operands are converted in the same way as Add, but arrays are always unsupported.
+//
+// The result is int64 if both operands are integers and the division has no remainder, otherwise float64.
+//
+// Division by zero behaves differently between PHP versions:
+//   - PHP 5.6 emits a warning "Division by zero" and returns false.
+//   - PHP 7 emits a warning "Division by zero" and returns INF, -INF or NAN.
+//   - PHP 8 throws DivisionByZeroError, for which this function returns error "Division by zero".
+//
+// Reference:
+//   - https://github.com/php/php-src/blob/php-5.6.40/Zend/zend_operators.c
+//   - https://github.com/php/php-src/blob/php-7.4.0/Zend/zend_operators.c
+//   - https://github.com/php/php-src/blob/php-8.3.0/Zend/zend_operators.c
+func (r *Runtime) Div(a, b any) (any, error) {
+	if err := checkOperands(a, b); err != nil {
+		return nil, err
+	}
+	n1, n2, err := r.operandsToNumbers(a, b, "/")
+	if err != nil {
+		return nil, err
+	}
+
+	if toDouble(n2) == 0 {
+		switch {
+		case r.Version >= PHP80:
+			return nil, errDivisionByZero
+		case r.Version >= PHP70:
+			return toDouble(n1) / toDouble(n2), nil
+		default:
+			return false, nil
+		}
+	}
+	if l1, l2, ok := bothLong(n1, n2); ok {
+		if l2 == -1 && l1 == math.MinInt64 {
+			// Prevent overflow error/crash
+			return float64(l1) / -1, nil
+		}
+		if l1%l2 == 0 {
+			// integer
+			return l1 / l2, nil
+		}
+		return float64(l1) / float64(l2), nil
+	}
+	return toDouble(n1) / toDouble(n2), nil
+}
+
+// Div works the same as Runtime.Div of a Runtime emulating PHP 5.6.
+func Div(a, b any) (any, error) {
+	return defaultRuntime.Div(a, b)
+}
+
+// Mod is a ported function that works exactly the same as PHP's mod_function, which implements the % operator.
+// The result is always int64 whose sign is the same as a.
+//
+// Unlike other arithmetic operators, operands are converted to integers:
+//   - Floats are truncated toward zero.
+//   - Strings are converted like (int) casts, so "1e3" is 1 before PHP 7.1, and 1000 since PHP 7.1.
+//     Non-numeric strings are unsupported operands since PHP 8.
+//   - Arrays are 0 if empty, otherwise 1 before PHP 8, and are unsupported operands since PHP 8.
+//   - Other values are converted in the same way as Add.
+//
+// Modulo by zero behaves differently between PHP versions:
+//   - PHP 5.6 emits a warning "Division by zero" and returns false.
+//   - PHP 7 and later throw DivisionByZeroError, for which this function returns error "Modulo by zero".
+//
+// Reference:
+//   - https://github.com/php/php-src/blob/php-5.6.40/Zend/zend_operators.c
+//   - https://github.com/php/php-src/blob/php-8.3.0/Zend/zend_operators.c
+func (r *Runtime) Mod(a, b any) (any, error) {
+	if err := checkOperands(a, b); err != nil {
+		return nil, err
+	}
+	l1, ok1 := r.operandToLong(a)
+	l2, ok2 := r.operandToLong(b)
+	if !ok1 || !ok2 {
+		return nil, r.unsupportedOperands(a, b, "%")
+	}
+
+	if l2 == 0 {
+		if r.Version >= PHP70 {
+			return nil, errModuloByZero
+		}
+		return false, nil
+	}
+	if l2 == -1 {
+		// Prevent overflow error/crash if op1 == LONG_MIN
+		return int64(0), nil
+	}
+	return l1 % l2, nil
+}
+
+// Mod works the same as Runtime.Mod of a Runtime emulating PHP 5.6.
+func Mod(a, b any) (any, error) {
+	return defaultRuntime.Mod(a, b)
+}
+
+// Pow is a ported function that works exactly the same as PHP's pow_function, which implements the **
+// operator. Operands are converted in the same way as Add.
+//
+// The result is int64 if both operands are integers, the exponent is not negative and the result does not
+// overflow, otherwise float64.
+//
+// Before PHP 8, an array as the base results in 0, and an array as the exponent results in 1. Since PHP 8,
+// arrays are unsupported operands.
+//
+// Reference:
+//   - https://github.com/php/php-src/blob/php-5.6.40/Zend/zend_operators.c
+//   - https://github.com/php/php-src/blob/php-8.3.0/Zend/zend_operators.c
+func (r *Runtime) Pow(a, b any) (any, error) {
+	if err := checkOperands(a, b); err != nil {
+		return nil, err
+	}
+	if r.Version < PHP80 {
+		if zvalTypeOf(a) == typeArray {
+			return int64(0), nil
+		}
+		if zvalTypeOf(b) == typeArray {
+			return int64(1), nil
+		}
+	}
+	n1, n2, err := r.operandsToNumbers(a, b, "**")
+	if err != nil {
+		return nil, err
+	}
+
+	l1, i, ok := bothLong(n1, n2)
+	if !ok || i < 0 {
+		return math.Pow(toDouble(n1), toDouble(n2)), nil
+	}
+	if i == 0 {
+		return int64(1), nil
+	} else if l1 == 0 {
+		return int64(0), nil
+	}
+
+	// calculate pow(long,long) in O(log exp) operations, bail if overflow
+	l2 := int64(1)
+	for i >= 1 {
+		if i%2 != 0 {
+			i--
+			l, d, overflow := signedMultiplyLong(l2, l1)
+			if overflow {
+				return d * math.Pow(float64(l1), float64(i)), nil
+			}
+			l2 = l
+		} else {
+			i /= 2
+			l, d, overflow := signedMultiplyLong(l1, l1)
+			if overflow {
+				return float64(l2) * math.Pow(d, float64(i)), nil
+			}
+			l1 = l
+		}
+	}
+	// i == 0
+	return l2, nil
+}
+
+// Pow works the same as Runtime.Pow of a Runtime emulating PHP 5.6.
+func Pow(a, b any) (any, error) {
+	return defaultRuntime.Pow(a, b)
+}
+
+// Neg implements PHP's unary - operator. PHP 5.6 compiles -$a into 0 - $a, and PHP 7 and later compile it into
+// $a * -1, so it works the same as Sub(0, a) or Mul(a, -1) respectively. Note that the results differ for
+// 0.0, which is 0.0 on PHP 5.6 and -0.0 on PHP 7 and later.
+//
+// Reference:
+//   - https://github.com/php/php-src/blob/php-5.6.40/Zend/zend_language_parser.y
+//   - https://github.com/php/php-src/blob/php-8.3.0/Zend/zend_compile.c
+func (r *Runtime) Neg(a any) (any, error) {
+	if r.Version >= PHP70 {
+		return r.Mul(a, int64(-1))
+	}
+	return r.Sub(int64(0), a)
+}
+
+// Neg works the same as Runtime.Neg of a Runtime emulating PHP 5.6.
+func Neg(a any) (any, error) {
+	return defaultRuntime.Neg(a)
+}
+
+// checkOperands returns error if any of the given operands is not supported by
+// this package.
+func checkOperands(a, b any) error {
+	if zvalTypeOf(a) == typeUnsupported {
+		return &UnsupportedTypeError{a}
+	}
+	if zvalTypeOf(b) == typeUnsupported {
+		return &UnsupportedTypeError{b}
+	}
+	return nil
+}
+
+// operandsToNumbers converts the given operands to either int64 or float64,
+// like PHP's zendi_convert_scalar_to_number. It returns error if any of them
+// is unsupported by the operator.
+func (r *Runtime) operandsToNumbers(a, b any, operator string) (any, any, error) {
+	n1, ok1 := r.operandToNumber(a)
+	n2, ok2 := r.operandToNumber(b)
+	if !ok1 || !ok2 {
+		return nil, nil, r.unsupportedOperands(a, b, operator)
+	}
+	return n1, n2, nil
+}
+
+// operandToNumber converts the given operand to either int64 or float64. It
+// returns false if the operand is unsupported by arithmetic operators.
+func (r *Runtime) operandToNumber(value any) (any, bool) {
+	switch zvalTypeOf(value) {
+	case typeLong:
+		return longOf(value), true
+	case typeDouble:
+		return doubleOf(value), true
+	case typeNull:
+		return int64(0), true
+	case typeBool:
+		if value.(bool) {
+			return int64(1), true
+		}
+		return int64(0), true
+	case typeString:
+		ns := r.numericOperand(value.(string))
+		switch ns.Type {
+		case NumericLong:
+			return ns.Long, true
+		case NumericDouble:
+			return ns.Double, true
+		default:
+			// PHP 8 throws TypeError for non-numeric strings
+			return int64(0), r.Version < PHP80
+		}
+	case typeResource:
+		l, _ := r.ConvertToLong(value)
+		return l, true
+	case typeObject:
+		// PHP emits a notice "Object of class X could not be converted to int"
+		// before PHP 8, and throws TypeError since PHP 8.
+		return int64(1), r.Version < PHP80
+	default:
+		return nil, false
+	}
+}
+
+// numericOperand parses a string operand as a numeric string, and emits the
+// diagnostics for invalid strings which PHP 7.1 and later emit. Leading
+// numeric strings like "1abc" emit E_NOTICE "A non well formed numeric value
+// encountered" before PHP 8, and E_WARNING "A non-numeric value encountered"
+// since PHP 8. Non-numeric strings emit E_WARNING "A non-numeric value
+// encountered" before PHP 8, and PHP 8 throws TypeError for them instead.
+//
+// Reference:
+//   - https://wiki.php.net/rfc/invalid_strings_in_arithmetic
+//   - https://wiki.php.net/rfc/saner-string-to-number
+func (r *Runtime) numericOperand(s string) NumericString {
+	allowErrors := AllowErrorsSilently
+	if r.Version >= PHP71 {
+		allowErrors = AllowErrorsWithNotice
+	}
+	ns := r.IsNumericString(s, allowErrors)
+	switch {
+	case ns.Type == NotNumeric && r.Version >= PHP71 && r.Version < PHP80:
+		r.emit(EWarning, "A non-numeric value encountered")
+	case ns.TrailingData && r.Version >= PHP80:
+		r.emit(EWarning, "A non-numeric value encountered")
+	}
+	return ns
+}
+
+// operandToLong converts the given operand to int64, like PHP's
+// zendi_convert_to_long. It returns false if the operand is unsupported by
+// arithmetic operators.
+func (r *Runtime) operandToLong(value any) (int64, bool) {
+	switch zvalTypeOf(value) {
+	case typeDouble:
+		return r.dvalToLval(doubleOf(value)), true
+	case typeString:
+		if r.Version < PHP71 {
+			return strtol(value.(string), 10), true
+		}
+		ns := r.numericOperand(value.(string))
+		switch ns.Type {
+		case NumericLong:
+			return ns.Long, true
+		case NumericDouble:
+			return zendDvalToLvalCap(ns.Double), true
+		default:
+			return 0, r.Version < PHP80
+		}
+	case typeArray:
+		if r.Version >= PHP80 {
+			return 0, false
+		}
+		l, _ := r.ConvertToLong(value)
+		return l, true
+	default:
+		n, ok := r.operandToNumber(value)
+		if !ok {
+			return 0, false
+		}
+		return n.(int64), true
+	}
+}
+
+// unsupportedOperands returns the error PHP raises when the operands are not
+// supported by the operator. PHP 8 throws TypeError, and earlier versions
+// raise a fatal error.
+func (r *Runtime) unsupportedOperands(a, b any, operator string) error {
+	if r.Version >= PHP80 {
+		return &TypeError{Message: fmt.Sprintf("Unsupported operand types: %s %s %s", zendZvalTypeName(a), operator, zendZvalTypeName(b))}
+	}
+	return &TypeError{Message: "Unsupported operand types"}
+}
+
+// arrayUnion returns the union of the given arrays as PHP's + operator does.
+// Nested *Array values are copied, since PHP's arrays are values.
+func arrayUnion(a, b any) (*Array, error) {
+	ret := NewArray()
+	for _, entries := range [][]arrayEntry{aggregateEntries(a), aggregateEntries(b)} {
+		for _, e := range entries {
+			if _, ok := ret.Get(e.key); ok {
+				continue
+			}
+			value := e.value
+			if sub, ok := value.(*Array); ok {
+				value = sub.Copy()
+			}
+			if err := ret.Set(e.key, value); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return ret, nil
+}
+
+// signedMultiplyLong is a ported macro that works exactly the same as PHP's
+// ZEND_SIGNED_MULTIPLY_LONG macro. It returns the product as int64, or as
+// float64 with overflow flag if the product overflows.
+//
+// Reference:
+//   - https://github.com/php/php-src/blob/php-5.6.40/Zend/zend_multiply.h
+func signedMultiplyLong(a, b int64) (int64, float64, bool) {
+	product := a * b
+	if a != 0 && (product/a != b || (a == -1 && b == math.MinInt64) || (b == -1 && a == math.MinInt64)) {
+		return 0, float64(a) * float64(b), true
+	}
+	return product, 0, false
+}
+
+// bothLong returns the given numbers as int64 if both of them are int64.
+func bothLong(n1, n2 any) (int64, int64, bool) {
+	l1, ok1 := n1.(int64)
+	l2, ok2 := n2.(int64)
+	return l1, l2, ok1 && ok2
+}
+
+// toDouble converts the given int64 or float64 to float64.
+func toDouble(n any) float64 {
+	if l, ok := n.(int64); ok {
+		return float64(l)
+	}
+	return n.(float64)
+}
@@ -0,0 +1,300 @@
+package gophplib
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func ExampleAdd() {
+	fmt.Println(Add(1, 2))
+	fmt.Println(Add(1, 2.5))
+	fmt.Println(Add("10", "5.5"))
+	fmt.Println(Add("3 apples", 2))
+	fmt.Println(Add(math.MaxInt64, 1))
+
+	union, _ := Add(omap("a", 1, "b", 2), omap("b", 3, "c", 4))
+	fmt.Println(dumpOrderedMap(union.(*Array).ToOrderedMap()))
+
+	fmt.Println(Add([]int{1}, 1))
+	fmt.Println(NewRuntime(PHP80).Add("abc", 1))
+	// Output:
+	// 3 <nil>
+	// 3.5 <nil>
+	// 15.5 <nil>
+	// 5 <nil>
+	// 9.223372036854776e+18 <nil>
+	// omap[a:1 b:2 c:4]
+	// <nil> Unsupported operand types
+	// <nil> Unsupported operand types: string + int
+}
+
+func ExampleDiv() {
+	fmt.Println(Div(6, 3))
+	fmt.Println(Div(7, 2))
+	fmt.Println(Div(1, 0))
+	fmt.Println(NewRuntime(PHP74).Div(1, 0))
+	fmt.Println(NewRuntime(PHP80).Div(1, 0))
+	// Output:
+	// 2 <nil>
+	// 3.5 <nil>
+	// false <nil>
+	// +Inf <nil>
+	// <nil> Division by zero
+}
+
+func ExampleMod() {
+	fmt.Println(Mod(7, 3))
+	fmt.Println(Mod(-7, 3))
+	fmt.Println(Mod(7.9, "3.9"))
+	fmt.Println(Mod(1, 0))
+	fmt.Println(NewRuntime(PHP80).Mod(1, 0))
+	// Output:
+	// 1 <nil>
+	// -1 <nil>
+	// 1 <nil>
+	// false <nil>
+	// <nil> Modulo by zero
+}
+
+func ExamplePow() {
+	fmt.Println(Pow(2, 10))
+	fmt.Println(Pow(2, -1))
+	fmt.Println(Pow(2, 63))
+	fmt.Println(Pow("3", 2.0))
+	// Output:
+	// 1024 <nil>
+	// 0.5 <nil>
+	// 9.223372036854776e+18 <nil>
+	// 9 <nil>
+}
+
+type arithmeticTestCase struct {
+	a, b  any
+	php56 any
+	php74 any
+	php80 any
+}
+
+// unsupported is the expected result of arithmeticTestCase when the operation
+// returns error.
+type unsupported string
+
+func testArithmetic(t *testing.T, name string, testCases []arithmeticTestCase, f func(r *Runtime, a, b any) (any, error)) {
+	for _, tc := range testCases {
+		for _, c := range []struct {
+			runtime  *Runtime
+			expected any
+		}{{NewRuntime(PHP56), tc.php56}, {NewRuntime(PHP74), tc.php74}, {NewRuntime(PHP80), tc.php80}} {
+			result, err := f(c.runtime, tc.a, tc.b)
+			if expected, ok := c.expected.(unsupported); ok {
+				if err == nil || err.Error() != string(expected) {
+					t.Errorf("%s(%#v, %#v) on PHP %d = %#v, %v; want error %q", name, tc.a, tc.b, c.runtime.Version, result, err, expected)
+				}
+				continue
+			}
+
+			matched := err == nil && fmt.Sprintf("%T", result) == fmt.Sprintf("%T", c.expected)
+			if f, ok := c.expected.(float64); ok && math.IsNaN(f) {
+				matched = matched && math.IsNaN(result.(float64))
+			} else {
+				matched = matched && result == c.expected
+			}
+			if !matched {
+				t.Errorf("%s(%#v, %#v) on PHP %d = %#v, %v; want %#v", name, tc.a, tc.b, c.runtime.Version, result, err, c.expected)
+			}
+		}
+	}
+}
+
+// TestAdd tests Add function.
+//
+// References:
+//   - https://github.com/php/php-src/blob/php-5.6.40/tests/lang/operators/add_basiclong_64bit.phpt
+//   - https://github.com/php/php-src/blob/php-5.6.40/tests/lang/operators/add_variationStr.phpt
+//   - https://github.com/php/php-src/blob/php-8.3.0/Zend/tests/add_006.phpt
+func TestAdd(t *testing.T) {
+	file := getFile()
+	defer file.Close()
+	fileID, _ := ConvertToLong(file)
+
+	testArithmetic(t, "Add", []arithmeticTestCase{
+		{1, 2, int64(3), int64(3), int64(3)},
+		{int8(-1), int32(1), int64(0), int64(0), int64(0)},
+		{math.MaxInt64, 1, float64(math.MaxInt64) + 1, float64(math.MaxInt64) + 1, float64(math.MaxInt64) + 1},
+		{math.MinInt64, -1, float64(math.MinInt64) - 1, float64(math.MinInt64) - 1, float64(math.MinInt64) - 1},
+		{math.MaxInt64, math.MinInt64, int64(-1), int64(-1), int64(-1)},
+		{1.5, 1, 2.5, 2.5, 2.5},
+		{float32(0.5), 0.25, 0.75, 0.75, 0.75},
+		{"1", "2", int64(3), int64(3), int64(3)},
+		{"1.5", 1, 2.5, 2.5, 2.5},
+		{"1e3", 1, 1001.0, 1001.0, 1001.0},
+		{" 1", 1, int64(2), int64(2), int64(2)},
+		{"1 ", 1, int64(2), int64(2), int64(2)},
+		{"123abc", 1, int64(124), int64(124), int64(124)},
+		{"0x1A", 0, int64(26), int64(0), int64(0)},
+		{"abc", 1, int64(1), int64(1), unsupported("Unsupported operand types: string + int")},
+		{"", 1, int64(1), int64(1), unsupported("Unsupported operand types: string + int")},
+		{"9223372036854775808", 0, 9223372036854775808.0, 9223372036854775808.0, 9223372036854775808.0},
+		{nil, nil, int64(0), int64(0), int64(0)},
+		{true, true, int64(2), int64(2), int64(2)},
+		{false, 1.5, 1.5, 1.5, 1.5},
+		{file, 0, fileID, fileID, fileID},
+		{Point{}, 1, int64(2), int64(2), unsupported("Unsupported operand types: Point + int")},
+		{1, &Bird{}, int64(2), int64(2), unsupported("Unsupported operand types: int + Bird")},
+		{[]int{1}, 1, unsupported("Unsupported operand types"), unsupported("Unsupported operand types"), unsupported("Unsupported operand types: array + int")},
+		{nil, []int{1}, unsupported("Unsupported operand types"), unsupported("Unsupported operand types"), unsupported("Unsupported operand types: null + array")},
+		{uint(1), 1, unsupported("unsupported type : uint"), unsupported("unsupported type : uint"), unsupported("unsupported type : uint")},
+	}, (*Runtime).Add)
+}
+
+// TestAddArrays tests Add function with arrays.
+func TestAddArrays(t *testing.T) {
+	testCases := []struct {
+		a, b     any
+		expected any
+	}{
+		{[]int{}, []int{}, omap()},
+		{[]int{1, 2}, []int{3, 4, 5}, omap(0, 1, 1, 2, 2, 5)},
+		{omap("a", 1), map[string]int{"a": 2, "b": 3}, omap("a", 1, "b", 3)},
+		{omap("1", "x"), []string{"y", "z"}, omap(1, "x", 0, "y")},
+	}
+
+	for _, tc := range testCases {
+		result, err := Add(tc.a, tc.b)
+		if err != nil || !Identical(result, tc.expected) {
+			t.Errorf("Add(%#v, %#v) = %#v, %v; want %#v", tc.a, tc.b, result, err, tc.expected)
+		}
+	}
+
+	nested := ParseStrArray("a[b]=1")
+	result, _ := Add(nested, []int{})
+	sub, _ := result.(*Array).Get("a")
+	sub.(*Array).Set("b", "changed")
+	if !Identical(nested, omap("a", omap("b", "1"))) {
+		t.Errorf("Add modified its operand: %s", dumpOrderedMap(nested.ToOrderedMap()))
+	}
+}
+
+// TestSub tests Sub function.
+//
+// References:
+//   - https://github.com/php/php-src/blob/php-5.6.40/tests/lang/operators/subtract_basiclong_64bit.phpt
+func TestSub(t *testing.T) {
+	testArithmetic(t, "Sub", []arithmeticTestCase{
+		{3, 2, int64(1), int64(1), int64(1)},
+		{math.MinInt64, 1, float64(math.MinInt64) - 1, float64(math.MinInt64) - 1, float64(math.MinInt64) - 1},
+		{math.MaxInt64, -1, float64(math.MaxInt64) + 1, float64(math.MaxInt64) + 1, float64(math.MaxInt64) + 1},
+		{-1, math.MaxInt64, int64(math.MinInt64), int64(math.MinInt64), int64(math.MinInt64)},
+		{0, math.MinInt64, -float64(math.MinInt64), -float64(math.MinInt64), -float64(math.MinInt64)},
+		{"10", "2.5", 7.5, 7.5, 7.5},
+		{"10 apples", "abc", int64(10), int64(10), unsupported("Unsupported operand types: string - string")},
+		{[]int{}, []int{}, unsupported("Unsupported operand types"), unsupported("Unsupported operand types"), unsupported("Unsupported operand types: array - array")},
+	}, (*Runtime).Sub)
+}
+
+// TestMul tests Mul function.
+//
+// References:
+//   - https://github.com/php/php-src/blob/php-5.6.40/tests/lang/operators/multiply_basiclong_64bit.phpt
+func TestMul(t *testing.T) {
+	testArithmetic(t, "Mul", []arithmeticTestCase{
+		{3, 2, int64(6), int64(6), int64(6)},
+		{-3, 2, int64(-6), int64(-6), int64(-6)},
+		{math.MaxInt64, 2, float64(math.MaxInt64) * 2, float64(math.MaxInt64) * 2, float64(math.MaxInt64) * 2},
+		{math.MinInt64, -1, -float64(math.MinInt64), -float64(math.MinInt64), -float64(math.MinInt64)},
+		{-1, math.MinInt64, -float64(math.MinInt64), -float64(math.MinInt64), -float64(math.MinInt64)},
+		{4294967296, 4294967296, 18446744073709551616.0, 18446744073709551616.0, 18446744073709551616.0},
+		{math.MaxInt64, -1, int64(-math.MaxInt64), int64(-math.MaxInt64), int64(-math.MaxInt64)},
+		{1.5, "2", 3.0, 3.0, 3.0},
+		{true, "3", int64(3), int64(3), int64(3)},
+		{nil, 10, int64(0), int64(0), int64(0)},
+	}, (*Runtime).Mul)
+}
+
+// TestDiv tests Div function.
+//
+// References:
+//   - https://github.com/php/php-src/blob/php-5.6.40/tests/lang/operators/divide_basiclong_64bit.phpt
+func TestDiv(t *testing.T) {
+	testArithmetic(t, "Div", []arithmeticTestCase{
+		{6, 3, int64(2), int64(2), int64(2)},
+		{-6, 3, int64(-2), int64(-2), int64(-2)},
+		{7, 2, 3.5, 3.5, 3.5},
+		{1, 3, 1.0 / 3, 1.0 / 3, 1.0 / 3},
+		{math.MinInt64, -1, -float64(math.MinInt64), -float64(math.MinInt64), -float64(math.MinInt64)},
+		{6.0, 3, 2.0, 2.0, 2.0},
+		{"6", "3", int64(2), int64(2), int64(2)},
+		{"1e1", 2, 5.0, 5.0, 5.0},
+		{1, 0, false, math.Inf(1), unsupported("Division by zero")},
+		{-1, 0.0, false, math.Inf(-1), unsupported("Division by zero")},
+		{0, 0, false, math.NaN(), unsupported("Division by zero")},
+		{1, "0", false, math.Inf(1), unsupported("Division by zero")},
+		{1, nil, false, math.Inf(1), unsupported("Division by zero")},
+		{1, "abc", false, math.Inf(1), unsupported("Unsupported operand types: int / string")},
+		{[]int{}, 1, unsupported("Unsupported operand types"), unsupported("Unsupported operand types"), unsupported("Unsupported operand types: array / int")},
+	}, (*Runtime).Div)
+}
+
+// TestMod tests Mod function.
+//
+// References:
+//   - https://github.com/php/php-src/blob/php-5.6.40/tests/lang/operators/modulus_basiclong_64bit.phpt
+//   - https://github.com/php/php-src/blob/php-8.3.0/Zend/tests/mod_001.phpt
+func TestMod(t *testing.T) {
+	testArithmetic(t, "Mod", []arithmeticTestCase{
+		{7, 3, int64(1), int64(1), int64(1)},
+		{-7, 3, int64(-1), int64(-1), int64(-1)},
+		{7, -3, int64(1), int64(1), int64(1)},
+		{math.MinInt64, -1, int64(0), int64(0), int64(0)},
+		{7.9, 3.9, int64(1), int64(1), int64(1)},
+		{"1e3", 7, int64(1), int64(6), int64(6)},
+		{"10 apples", 3, int64(1), int64(1), int64(1)},
+		{"abc", 3, int64(0), int64(0), unsupported("Unsupported operand types: string % int")},
+		{[]int{1}, 3, int64(1), int64(1), unsupported("Unsupported operand types: array % int")},
+		{Point{}, 3, int64(1), int64(1), unsupported("Unsupported operand types: Point % int")},
+		{math.Inf(1), 3, int64(-2), int64(0), int64(0)},
+		{1, 0, false, unsupported("Modulo by zero"), unsupported("Modulo by zero")},
+		{1, 0.5, false, unsupported("Modulo by zero"), unsupported("Modulo by zero")},
+		{1, []int{}, false, unsupported("Modulo by zero"), unsupported("Unsupported operand types: int % array")},
+	}, (*Runtime).Mod)
+}
+
+// TestPow tests Pow function.
+func TestPow(t *testing.T) {
+	testArithmetic(t, "Pow", []arithmeticTestCase{
+		{2, 0, int64(1), int64(1), int64(1)},
+		{0, 0, int64(1), int64(1), int64(1)},
+		{0, 5, int64(0), int64(0), int64(0)},
+		{2, 10, int64(1024), int64(1024), int64(1024)},
+		{-2, 3, int64(-8), int64(-8), int64(-8)},
+		{3, 39, int64(4052555153018976267), int64(4052555153018976267), int64(4052555153018976267)},
+		{3, 40, 1.2157665459056929e+19, 1.2157665459056929e+19, 1.2157665459056929e+19},
+		{-2, 63, int64(math.MinInt64), int64(math.MinInt64), int64(math.MinInt64)},
+		{2, 63, -float64(math.MinInt64), -float64(math.MinInt64), -float64(math.MinInt64)},
+		{2, -2, 0.25, 0.25, 0.25},
+		{0, -1, math.Inf(1), math.Inf(1), math.Inf(1)},
+		{2.5, 2, 6.25, 6.25, 6.25},
+		{"2", "3", int64(8), int64(8), int64(8)},
+		{[]int{1}, 2, int64(0), int64(0), unsupported("Unsupported operand types: array ** int")},
+		{2, []int{1}, int64(1), int64(1), unsupported("Unsupported operand types: int ** array")},
+	}, (*Runtime).Pow)
+}
+
+func TestNeg(t *testing.T) {
+	testArithmetic(t, "Neg", []arithmeticTestCase{
+		{1, nil, int64(-1), int64(-1), int64(-1)},
+		{math.MinInt64, nil, -float64(math.MinInt64), -float64(math.MinInt64), -float64(math.MinInt64)},
+		{"1.5", nil, -1.5, -1.5, -1.5},
+		{0.0, nil, 0.0, math.Copysign(0, -1), math.Copysign(0, -1)},
+		{[]int{}, nil, unsupported("Unsupported operand types"), unsupported("Unsupported operand types"), unsupported("Unsupported operand types: array * int")},
+	}, func(r *Runtime, a, _ any) (any, error) { return r.Neg(a) })
+
+	// 0.0 and -0.0 are equal, so check the sign bit
+	for version, negative := range map[Version]bool{PHP56: false, PHP74: true} {
+		result, _ := NewRuntime(version).Neg(0.0)
+		if math.Signbit(result.(float64)) != negative {
+			t.Errorf("Neg(0.0) on PHP %d = %v; want negative: %v", version, result, negative)
+		}
+	}
+}
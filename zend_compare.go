@@ -0,0 +1,589 @@
+package gophplib
+
+import (
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// errNestingTooDeep is returned when comparing values which are nested too
+// deeply, which usually means that they have a circular reference.
+var errNestingTooDeep error = &Error{Message: "Nesting level too deep - recursive dependency?"}
+
+// maxNestingLevel is the maximum depth of nested arrays and objects which can be
+// compared.
+const maxNestingLevel = 256
+
+// LooseEquals reports whether the given values are equal in terms of PHP's loose comparison operator (==). For
+// more information, see the [official PHP documentation].
+//
+// It is the same as checking whether Compare returns 0, except that NaN is never equal to any number, which is
+// the same as PHP's == operator.
+//
+// The comparison rules differ between PHP versions. The most notable differences are as follows:
+//   - PHP 5.6 and PHP 7 compare a number with a string by converting the string to a number, so 0 == "abc" is
+//     true and 123 == "123abc" is true. PHP 8 compares them as strings unless the string is numeric, so both of
+//     them are false.
+//   - PHP 5.6 treats hexadecimal strings as numeric, so "0x1A" == "26" is true only in PHP 5.6.
+//   - PHP 8 treats numeric strings with trailing whitespaces as numeric, so "1 " == "1.0" is true only in PHP 8.
+//
+// This function returns error if any of the given values is not one of following:
+// string, int, int8, int16, int32, int64, float32, float64, bool, nil, *os.File, *net.Conn, *sql.DB, the types
+// registered with RegisterResourceType, array, slice, map, orderedmap.OrderedMap, Array, struct, pointer to struct
+// and any type which implements Stringable.
+//
+// Reference:
+//   - https://github.com/php/php-src/blob/php-5.6.40/Zend/zend_operators.h
+//   - https://github.com/php/php-src/blob/php-8.3.0/Zend/zend_operators.c
+//
+// [official PHP documentation]: https://www.php.net/manual/en/language.operators.comparison.php
+func (r *Runtime) LooseEquals(a, b any) (bool, error) {
+	// Emulate fast_equal_function, which compares numbers directly
+	t1, t2 := zvalTypeOf(a), zvalTypeOf(b)
+	if (t1 == typeLong || t1 == typeDouble) && (t2 == typeLong || t2 == typeDouble) && !(t1 == typeLong && t2 == typeLong) {
+		return doubleOf(a) == doubleOf(b), nil
+	}
+
+	ret, err := r.Compare(a, b)
+	if err != nil {
+		return false, err
+	}
+	return ret == 0, nil
+}
+
+// LooseEquals works the same as Runtime.LooseEquals of a Runtime emulating PHP 5.6.
+func LooseEquals(a, b any) (bool, error) {
+	return defaultRuntime.LooseEquals(a, b)
+}
+
+// Compare compares the given values in the same way as PHP's compare_function, which is used by PHP's
+// comparison operators. It returns -1, 0 or 1 if a is less than, equal to, or greater than b respectively,
+// which is the same as PHP's spaceship operator (<=>). For more information, see the
+// [official PHP documentation].
+//
+// The comparison rules are as follows:
+//   - nil and bool are compared with any value by converting the other value to bool.
+//   - Numbers and numeric strings are compared numerically. See LooseEquals for the differences of comparing
+//     numbers with non-numeric strings between PHP versions.
+//   - Non-numeric strings are compared byte by byte.
+//   - Arrays with fewer elements are smaller. Arrays with the same number of elements are compared element by
+//     element using the keys of a, and they are uncomparable (the result is 1) if b lacks any key of a.
+//   - Arrays are always greater than any other values, except objects and nil.
+//   - Objects of the same type are compared field by field, and objects of different types are uncomparable.
+//   - Objects are compared with strings by using their ToString method if they implement Stringable, and with
+//     bool by converting them to bool. Objects are converted to 1 when compared with numbers, for which PHP
+//     emits a notice "Object of class X could not be converted to int".
+//
+// Note that Go maps do not preserve insertion order, so the result of comparing them with other arrays may
+// vary if they differ in more than one element. Use orderedmap.OrderedMap to get the stable result.
+//
+// This function returns error if any of the given values is not supported. See LooseEquals for the list of
+// supported types.
+//
+// Reference:
+//   - https://github.com/php/php-src/blob/php-5.6.40/Zend/zend_operators.c
+//   - https://github.com/php/php-src/blob/php-8.3.0/Zend/zend_operators.c
+//   - https://github.com/php/php-src/blob/php-8.3.0/Zend/zend_object_handlers.c
+//
+// [official PHP documentation]: https://www.php.net/manual/en/language.operators.comparison.php
+func (r *Runtime) Compare(a, b any) (int, error) {
+	return r.compare(a, b, 0)
+}
+
+// Compare works the same as Runtime.Compare of a Runtime emulating PHP 5.6.
+func Compare(a, b any) (int, error) {
+	return defaultRuntime.Compare(a, b)
+}
+
+func (r *Runtime) compare(op1, op2 any, depth int) (int, error) {
+	if depth > maxNestingLevel {
+		return 0, errNestingTooDeep
+	}
+
+	converted := false
+	for {
+		t1, t2 := zvalTypeOf(op1), zvalTypeOf(op2)
+		switch {
+		case t1 == typeUnsupported:
+			return 0, &UnsupportedTypeError{op1}
+		case t2 == typeUnsupported:
+			return 0, &UnsupportedTypeError{op2}
+
+		case t1 == typeLong && t2 == typeLong:
+			return threeWayCompare(longOf(op1), longOf(op2)), nil
+		case (t1 == typeLong || t1 == typeDouble) && (t2 == typeLong || t2 == typeDouble):
+			return r.compareDoubles(doubleOf(op1), doubleOf(op2)), nil
+		case t1 == typeArray && t2 == typeArray:
+			return r.compareArrays(op1, op2, depth)
+
+		case t1 == typeNull && t2 == typeNull:
+			return 0, nil
+		case t1 == typeNull && t2 == typeBool:
+			return -boolToInt(op2.(bool)), nil
+		case t1 == typeBool && t2 == typeNull:
+			return boolToInt(op1.(bool)), nil
+		case t1 == typeBool && t2 == typeBool:
+			return boolToInt(op1.(bool)) - boolToInt(op2.(bool)), nil
+
+		case t1 == typeString && t2 == typeString:
+			return r.smartStrcmp(op1.(string), op2.(string)), nil
+		case t1 == typeNull && t2 == typeString:
+			return normalize(strings.Compare("", op2.(string))), nil
+		case t1 == typeString && t2 == typeNull:
+			return normalize(strings.Compare(op1.(string), "")), nil
+
+		case t1 == typeObject && t2 == typeNull:
+			return 1, nil
+		case t1 == typeNull && t2 == typeObject:
+			return -1, nil
+
+		// PHP 8 compares numbers with non-numeric strings as strings
+		case r.Version >= PHP80 && t1 == typeLong && t2 == typeString:
+			return r.compareLongToString(longOf(op1), op2.(string)), nil
+		case r.Version >= PHP80 && t1 == typeString && t2 == typeLong:
+			return -r.compareLongToString(longOf(op2), op1.(string)), nil
+		case r.Version >= PHP80 && t1 == typeDouble && t2 == typeString:
+			if math.IsNaN(doubleOf(op1)) {
+				return 1, nil
+			}
+			return r.compareDoubleToString(doubleOf(op1), op2.(string)), nil
+		case r.Version >= PHP80 && t1 == typeString && t2 == typeDouble:
+			if math.IsNaN(doubleOf(op2)) {
+				return 1, nil
+			}
+			return -r.compareDoubleToString(doubleOf(op2), op1.(string)), nil
+
+		case t1 == typeObject && t2 == typeObject:
+			return r.compareObjects(op1, op2, depth)
+		case t1 == typeObject:
+			return r.compareObjectToValue(op1, op2, true, depth)
+		case t2 == typeObject:
+			return r.compareObjectToValue(op2, op1, false, depth)
+
+		case !converted:
+			switch {
+			case t1 == typeNull:
+				return -boolToInt(r.ConvertToBool(op2)), nil
+			case t2 == typeNull:
+				return boolToInt(r.ConvertToBool(op1)), nil
+			case t1 == typeBool:
+				return boolToInt(op1.(bool)) - boolToInt(r.ConvertToBool(op2)), nil
+			case t2 == typeBool:
+				return boolToInt(r.ConvertToBool(op1)) - boolToInt(op2.(bool)), nil
+			}
+			op1, op2 = r.scalarToNumber(op1), r.scalarToNumber(op2)
+			converted = true
+		case t1 == typeArray:
+			return 1, nil
+		case t2 == typeArray:
+			return -1, nil
+		default:
+			// unreachable
+			return 0, nil
+		}
+	}
+}
+
+// scalarToNumber converts the given value to either int64 or float64 without
+// emitting any diagnostics, like PHP's zendi_convert_scalar_to_number with
+// silent flag. Arrays are returned as is.
+func (r *Runtime) scalarToNumber(value any) any {
+	switch zvalTypeOf(value) {
+	case typeString:
+		ns := r.IsNumericString(value.(string), AllowErrorsSilently)
+		switch ns.Type {
+		case NumericLong:
+			return ns.Long
+		case NumericDouble:
+			return ns.Double
+		default:
+			return int64(0)
+		}
+	case typeArray, typeLong, typeDouble:
+		return value
+	default:
+		l, _ := r.ConvertToLong(value)
+		return l
+	}
+}
+
+// compareDoubles compares two floats. PHP 5.6 and PHP 7 return 0 if any of them
+// is NaN, and PHP 8 returns 1 in that case.
+func (r *Runtime) compareDoubles(d1, d2 float64) int {
+	if r.Version >= PHP80 {
+		return threeWayCompare(d1, d2)
+	}
+	if d1 == d2 {
+		return 0
+	}
+	return normalize(d1 - d2)
+}
+
+// smartStrcmp is a ported function that works exactly the same as PHP's
+// zendi_smart_strcmp function. It compares two strings numerically if both of
+// them are numeric, otherwise byte by byte.
+//
+// References:
+//   - https://github.com/php/php-src/blob/php-5.6.40/Zend/zend_operators.c
+//   - https://github.com/php/php-src/blob/php-8.3.0/Zend/zend_operators.c
+func (r *Runtime) smartStrcmp(s1, s2 string) int {
+	n1 := r.IsNumericString(s1, DisallowErrors)
+	if n1.Type == NotNumeric {
+		return normalize(strings.Compare(s1, s2))
+	}
+	n2 := r.IsNumericString(s2, DisallowErrors)
+	if n2.Type == NotNumeric {
+		return normalize(strings.Compare(s1, s2))
+	}
+
+	if n1.Overflow != 0 && n1.Overflow == n2.Overflow && n1.Double-n2.Double == 0 {
+		// both values are integers overflown to the same side, use string comparison
+		return normalize(strings.Compare(s1, s2))
+	}
+	if n1.Type == NumericDouble || n2.Type == NumericDouble {
+		d1, d2 := n1.Double, n2.Double
+		if n1.Type != NumericDouble {
+			if n2.Overflow != 0 {
+				// 2nd operand is integer > LONG_MAX (oflow2==1) or < LONG_MIN (-1)
+				return -1 * n2.Overflow
+			}
+			d1 = float64(n1.Long)
+		} else if n2.Type != NumericDouble {
+			if n1.Overflow != 0 {
+				return n1.Overflow
+			}
+			d2 = float64(n2.Long)
+		} else if d1 == d2 && math.IsInf(d1, 0) {
+			// Both values overflowed and have the same sign, so a numeric
+			// comparison would be inaccurate
+			return normalize(strings.Compare(s1, s2))
+		}
+		return r.compareDoubles(d1, d2)
+	}
+	return threeWayCompare(n1.Long, n2.Long)
+}
+
+// compareLongToString is a ported function that works exactly the same as PHP
+// 8's compare_longs_to_string function.
+//
+// References:
+//   - https://github.com/php/php-src/blob/php-8.3.0/Zend/zend_operators.c
+func (r *Runtime) compareLongToString(l int64, s string) int {
+	ns := r.IsNumericString(s, DisallowErrors)
+	switch ns.Type {
+	case NumericLong:
+		return threeWayCompare(l, ns.Long)
+	case NumericDouble:
+		return threeWayCompare(float64(l), ns.Double)
+	default:
+		return normalize(strings.Compare(strconv.FormatInt(l, 10), s))
+	}
+}
+
+// compareDoubleToString is a ported function that works exactly the same as PHP
+// 8's compare_doubles_to_string function.
+//
+// References:
+//   - https://github.com/php/php-src/blob/php-8.3.0/Zend/zend_operators.c
+func (r *Runtime) compareDoubleToString(d float64, s string) int {
+	ns := r.IsNumericString(s, DisallowErrors)
+	switch ns.Type {
+	case NumericLong:
+		return threeWayCompare(d, float64(ns.Long))
+	case NumericDouble:
+		return threeWayCompare(d, ns.Double)
+	default:
+		return normalize(strings.Compare(r.floatToString(d), s))
+	}
+}
+
+// compareArrays is a ported function that works exactly the same as PHP's
+// zend_compare_arrays function, which compares arrays with zend_hash_compare
+// without considering the order of keys.
+//
+// References:
+//   - https://github.com/php/php-src/blob/php-8.3.0/Zend/zend_hash.c
+func (r *Runtime) compareArrays(a1, a2 any, depth int) (int, error) {
+	e1, e2 := aggregateEntries(a1), aggregateEntries(a2)
+	if len(e1) != len(e2) {
+		return threeWayCompare(len(e1), len(e2)), nil
+	}
+
+	values := make(map[any]any, len(e2))
+	for _, e := range e2 {
+		values[e.key] = e.value
+	}
+	for _, e := range e1 {
+		v2, ok := values[e.key]
+		if !ok {
+			return 1, nil
+		}
+		ret, err := r.compare(e.value, v2, depth+1)
+		if err != nil || ret != 0 {
+			return ret, err
+		}
+	}
+	return 0, nil
+}
+
+// compareObjects is a ported function that works exactly the same as PHP's
+// zend_std_compare_objects function for two objects. The same objects are
+// equal, objects of different types are uncomparable, and objects of the same
+// type are compared field by field.
+//
+// References:
+//   - https://github.com/php/php-src/blob/php-8.3.0/Zend/zend_object_handlers.c
+func (r *Runtime) compareObjects(o1, o2 any, depth int) (int, error) {
+	v1, v2 := reflect.ValueOf(o1), reflect.ValueOf(o2)
+	if v1.Kind() == reflect.Pointer && v2.Kind() == reflect.Pointer && v1.Type() == v2.Type() && v1.Pointer() == v2.Pointer() {
+		// the same object
+		return 0, nil
+	}
+
+	v1, v2 = reflect.Indirect(v1), reflect.Indirect(v2)
+	if v1.Type() != v2.Type() || v1.Kind() != reflect.Struct {
+		// different classes
+		return 1, nil
+	}
+	for i := 0; i < v1.NumField(); i++ {
+		ret, err := r.compare(readValue(v1.Field(i)), readValue(v2.Field(i)), depth+1)
+		if err != nil || ret != 0 {
+			return ret, err
+		}
+	}
+	return 0, nil
+}
+
+// compareObjectToValue is a ported function that works exactly the same as
+// PHP's zend_std_compare_objects function for an object and a non-object value.
+// The object is converted to the type of the value and then compared, and they
+// are uncomparable if the object can not be converted.
+//
+// References:
+//   - https://github.com/php/php-src/blob/php-8.3.0/Zend/zend_object_handlers.c
+func (r *Runtime) compareObjectToValue(object, value any, objectLHS bool, depth int) (int, error) {
+	var casted any
+	switch zvalTypeOf(value) {
+	case typeBool:
+		casted = r.ConvertToBool(object)
+	case typeLong:
+		casted = int64(1)
+	case typeDouble:
+		casted = 1.0
+	case typeString:
+		s, ok := asStringable(object)
+		if !ok {
+			return uncomparable(objectLHS), nil
+		}
+		casted = s.ToString()
+	default:
+		return uncomparable(objectLHS), nil
+	}
+
+	if objectLHS {
+		return r.compare(casted, value, depth+1)
+	}
+	return r.compare(value, casted, depth+1)
+}
+
+// uncomparable returns the result of comparing an object with a value which the
+// object can not be converted to.
+func uncomparable(objectLHS bool) int {
+	if objectLHS {
+		return 1
+	}
+	return -1
+}
+
+// readValue returns the value held by v, even if v was obtained by accessing
+// unexported struct fields, which can not be read by v.Interface(). In that
+// case, integers are returned as int64, floats as float64, slices and arrays as
+// []any, structs as []any of their fields, and maps as map[any]any.
+func readValue(v reflect.Value) any {
+	if v.CanInterface() {
+		return v.Interface()
+	}
+	switch v.Kind() {
+	case reflect.Bool:
+		return v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int()
+	case reflect.Float32, reflect.Float64:
+		return v.Float()
+	case reflect.String:
+		return v.String()
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return readValue(v.Elem())
+	case reflect.Slice, reflect.Array:
+		ret := make([]any, v.Len())
+		for i := range ret {
+			ret[i] = readValue(v.Index(i))
+		}
+		return ret
+	case reflect.Struct:
+		ret := make([]any, v.NumField())
+		for i := range ret {
+			ret[i] = readValue(v.Field(i))
+		}
+		return ret
+	case reflect.Map:
+		ret := make(map[any]any, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			ret[readValue(iter.Key())] = readValue(iter.Value())
+		}
+		return ret
+	default:
+		return v
+	}
+}
+
+// threeWayCompare is a ported macro that works exactly the same as PHP's
+// ZEND_THREEWAY_COMPARE macro.
+func threeWayCompare[T int | int64 | float64](a, b T) int {
+	if a == b {
+		return 0
+	}
+	if a < b {
+		return -1
+	}
+	return 1
+}
+
+// normalize is a ported macro that works exactly the same as PHP's
+// ZEND_NORMALIZE_BOOL macro.
+func normalize[T int | int64 | float64](n T) int {
+	if n > 0 {
+		return 1
+	}
+	if n < 0 {
+		return -1
+	}
+	return 0
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// Identical reports whether the given values are identical in terms of PHP's identity operator (===). For more
+// information, see the [official PHP documentation].
+//
+// The rules are as follows:
+//   - Values of different types are never identical. Note that int, int8, int16, int32 and int64 are all
+//     integers, and float32 and float64 are all floats in PHP, so 1 and int64(1) are identical, but 1 and 1.0
+//     are not.
+//   - Floats are identical if they are equal, so NaN is not identical to itself.
+//   - Arrays are identical if they have the same key-value pairs in the same order and their values are
+//     identical. Keys are compared after PHP's key normalization, so []string{"a"} and a map with key "0" are
+//     identical. Go maps have no order, so arrays are compared regardless of their order if any of them is a
+//     Go map.
+//   - Pointers to structs and resources are identical only if they are the same value.
+//   - Structs are identical if they have the same type and their fields are identical, since they are copied
+//     by value in Go.
+//
+// Values of unsupported types are never identical. See LooseEquals for the list of supported types.
+//
+// Reference:
+//   - https://github.com/php/php-src/blob/php-5.6.40/Zend/zend_operators.c
+//   - https://github.com/php/php-src/blob/php-8.3.0/Zend/zend_operators.c
+//
+// [official PHP documentation]: https://www.php.net/manual/en/language.operators.comparison.php
+func Identical(a, b any) bool {
+	return identical(a, b, 0)
+}
+
+func identical(op1, op2 any, depth int) bool {
+	if depth > maxNestingLevel {
+		return false
+	}
+
+	t := zvalTypeOf(op1)
+	if t != zvalTypeOf(op2) {
+		return false
+	}
+	switch t {
+	case typeNull:
+		return true
+	case typeBool:
+		return op1.(bool) == op2.(bool)
+	case typeLong:
+		return longOf(op1) == longOf(op2)
+	case typeDouble:
+		return doubleOf(op1) == doubleOf(op2)
+	case typeString:
+		return op1.(string) == op2.(string)
+	case typeResource:
+		return op1 == op2
+	case typeArray:
+		return identicalArrays(op1, op2, depth)
+	case typeObject:
+		return identicalObjects(op1, op2, depth)
+	default:
+		return false
+	}
+}
+
+// identicalArrays is a ported function that works exactly the same as PHP's
+// zend_hash_compare function with ordered flag and hash_zval_identical_function.
+//
+// References:
+//   - https://github.com/php/php-src/blob/php-8.3.0/Zend/zend_hash.c
+func identicalArrays(a1, a2 any, depth int) bool {
+	e1, e2 := aggregateEntries(a1), aggregateEntries(a2)
+	if len(e1) != len(e2) {
+		return false
+	}
+
+	if reflect.ValueOf(a1).Kind() == reflect.Map || reflect.ValueOf(a2).Kind() == reflect.Map {
+		// Go maps have no order, so compare them without considering the order
+		values := make(map[any]any, len(e2))
+		for _, e := range e2 {
+			values[e.key] = e.value
+		}
+		for _, e := range e1 {
+			v2, ok := values[e.key]
+			if !ok || !identical(e.value, v2, depth+1) {
+				return false
+			}
+		}
+		return true
+	}
+
+	for i := range e1 {
+		if e1[i].key != e2[i].key || !identical(e1[i].value, e2[i].value, depth+1) {
+			return false
+		}
+	}
+	return true
+}
+
+// identicalObjects reports whether the given objects are identical. Pointers
+// are identical if they are the same, and structs are identical if their
+// fields are identical.
+func identicalObjects(o1, o2 any, depth int) bool {
+	v1, v2 := reflect.ValueOf(o1), reflect.ValueOf(o2)
+	if v1.Type() != v2.Type() {
+		return false
+	}
+	switch v1.Kind() {
+	case reflect.Pointer:
+		return v1.Pointer() == v2.Pointer()
+	case reflect.Struct:
+		for i := 0; i < v1.NumField(); i++ {
+			if !identical(readValue(v1.Field(i)), readValue(v2.Field(i)), depth+1) {
+				return false
+			}
+		}
+		return true
+	default:
+		return v1.Type().Comparable() && o1 == o2
+	}
+}
@@ -0,0 +1,317 @@
+package gophplib
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+type Point struct {
+	X, Y int
+}
+
+type temperature struct {
+	celsius float64
+	unit    string
+}
+
+func ExampleLooseEquals() {
+	fmt.Println(LooseEquals(0, "abc"))
+	fmt.Println(LooseEquals("1e3", "1000"))
+	fmt.Println(LooseEquals(123, "123abc"))
+	fmt.Println(LooseEquals(nil, false))
+	fmt.Println(LooseEquals([]int{1, 2}, map[int]string{1: "2", 0: "1"}))
+	fmt.Println(LooseEquals(math.NaN(), math.NaN()))
+
+	// PHP 8 compares numbers with non-numeric strings as strings
+	php8 := NewRuntime(PHP80)
+	fmt.Println(php8.LooseEquals(0, "abc"))
+	fmt.Println(php8.LooseEquals(123, "123abc"))
+	// Output:
+	// true <nil>
+	// true <nil>
+	// true <nil>
+	// true <nil>
+	// true <nil>
+	// false <nil>
+	// false <nil>
+	// false <nil>
+}
+
+func ExampleCompare() {
+	fmt.Println(Compare(1, 2))
+	fmt.Println(Compare("abc", "abd"))
+	fmt.Println(Compare("10", "9"))
+	fmt.Println(Compare([]int{1, 2, 3}, []int{4, 5}))
+	fmt.Println(Compare(Point{1, 2}, Point{1, 3}))
+	fmt.Println(Compare(struct{}{}, "abc"))
+	// Output:
+	// -1 <nil>
+	// -1 <nil>
+	// 1 <nil>
+	// 1 <nil>
+	// -1 <nil>
+	// 1 <nil>
+}
+
+// TestCompare tests Compare function for PHP 5.6, PHP 7.4 and PHP 8.0. Test
+// cases are from php-src's comparison tests.
+//
+// References:
+//   - https://github.com/php/php-src/blob/php-5.6.40/tests/lang/operators/comparison_equal_basiclong_64bit.phpt
+//   - https://github.com/php/php-src/blob/php-8.0.0/Zend/tests/numeric_strings/string_leading_and_trailing_whitespace.phpt
+//   - https://wiki.php.net/rfc/string_to_number_comparison
+func TestCompare(t *testing.T) {
+	bird := &Bird{"tweety"}
+	testCases := []struct {
+		a, b  any
+		php56 int
+		php74 int
+		php80 int
+	}{
+		// numbers
+		{1, 1, 0, 0, 0},
+		{1, 2, -1, -1, -1},
+		{int8(3), int64(2), 1, 1, 1},
+		{1, 1.0, 0, 0, 0},
+		{1.5, 1, 1, 1, 1},
+		{float32(0.5), 0.5, 0, 0, 0},
+		{math.NaN(), 1.0, 0, 0, 1},
+		{math.Inf(1), math.MaxInt64, 1, 1, 1},
+
+		// numeric strings
+		{"1e3", "1000", 0, 0, 0},
+		{"10", "9", 1, 1, 1},
+		{"abc", "abd", -1, -1, -1},
+		{"abc", "ab", 1, 1, 1},
+		{"1", "01", 0, 0, 0},
+		{"10", "1e1", 0, 0, 0},
+		{"100", "1e2", 0, 0, 0},
+		{"0x1A", "26", 0, -1, -1},
+		{"1 ", "1.0", -1, -1, 0},
+		{"9223372036854775807", "9223372036854775808", -1, -1, -1},
+		{"9223372036854775808", "9223372036854775809", -1, -1, -1},
+		{"1e1000", "2e1000", -1, -1, -1},
+
+		// numbers and strings
+		{0, "abc", 0, 0, -1},
+		{"abc", 0, 0, 0, 1},
+		{0, "", 0, 0, 1},
+		{"", 0, 0, 0, -1},
+		{123, "123abc", 0, 0, -1},
+		{"123abc", 123, 0, 0, 1},
+		{42, " 42", 0, 0, 0},
+		{42, "42 ", 0, 0, 0},
+		{"1e3", 1000, 0, 0, 0},
+		{1.5, "1.5", 0, 0, 0},
+		{1.5, "abc", 1, 1, -1},
+		{"abc", 1.5, -1, -1, 1},
+		{math.NaN(), "abc", 0, 0, 1},
+		{"abc", math.NaN(), 0, 0, 1},
+
+		// null and bool
+		{nil, nil, 0, 0, 0},
+		{nil, false, 0, 0, 0},
+		{nil, true, -1, -1, -1},
+		{true, nil, 1, 1, 1},
+		{true, false, 1, 1, 1},
+		{false, true, -1, -1, -1},
+		{nil, "", 0, 0, 0},
+		{nil, "a", -1, -1, -1},
+		{"a", nil, 1, 1, 1},
+		{nil, 0, 0, 0, 0},
+		{nil, -1, -1, -1, -1},
+		{true, "abc", 0, 0, 0},
+		{false, "0", 0, 0, 0},
+		{"0.0", true, 0, 0, 0},
+		{true, []int{}, 1, 1, 1},
+		{nil, []int{}, 0, 0, 0},
+		{nil, []int{1}, -1, -1, -1},
+
+		// arrays
+		{[]int{1, 2}, []int{1, 2}, 0, 0, 0},
+		{[]int{1, 2}, []int{1, 3}, -1, -1, -1},
+		{[]int{1, 2, 3}, []int{4, 5}, 1, 1, 1},
+		{[]int{1, 2}, []any{"1", "2.0"}, 0, 0, 0},
+		{[]int{1, 2}, map[int]int{1: 2, 0: 1}, 0, 0, 0},
+		{[]int{1}, map[string]int{"0": 1}, 0, 0, 0},
+		{map[string]int{"a": 1}, map[string]int{"b": 1}, 1, 1, 1},
+		{map[string]int{"b": 1}, map[string]int{"a": 1}, 1, 1, 1},
+		{omap("a", 1, "b", 2), omap("b", 2, "a", 1), 0, 0, 0},
+		{omap("a", 1, "b", 2), omap("b", 1, "a", 2), -1, -1, -1},
+		{[]any{[]int{1}}, []any{[]int{2}}, -1, -1, -1},
+		{[]int{}, 1, 1, 1, 1},
+		{"abc", []int{}, -1, -1, -1},
+
+		// objects
+		{Point{1, 2}, Point{1, 2}, 0, 0, 0},
+		{Point{1, 2}, &Point{1, 3}, -1, -1, -1},
+		{Point{2, 1}, Point{1, 3}, 1, 1, 1},
+		{temperature{1.5, "C"}, temperature{1.5, "C"}, 0, 0, 0},
+		{temperature{1.5, "C"}, temperature{1.5, "F"}, -1, -1, -1},
+		{Point{}, Sample{}, 1, 1, 1},
+		{Sample{}, Point{}, 1, 1, 1},
+		{bird, bird, 0, 0, 0},
+		{bird, "bird named tweety", 0, 0, 0},
+		{"bird named zazu", bird, 1, 1, 1},
+		{Point{}, "abc", 1, 1, 1},
+		{"abc", Point{}, -1, -1, -1},
+		{Point{}, 1, 0, 0, 0},
+		{Point{}, 2, -1, -1, -1},
+		{0.5, Point{}, -1, -1, -1},
+		{Point{}, true, 0, 0, 0},
+		{Point{}, nil, 1, 1, 1},
+		{nil, Point{}, -1, -1, -1},
+		{Point{}, []int{}, 1, 1, 1},
+		{[]int{}, Point{}, -1, -1, -1},
+		{&XMLElement{}, false, 0, 0, 0},
+	}
+
+	php56, php74, php80 := NewRuntime(PHP56), NewRuntime(PHP74), NewRuntime(PHP80)
+	for _, tc := range testCases {
+		for _, c := range []struct {
+			runtime  *Runtime
+			expected int
+		}{{php56, tc.php56}, {php74, tc.php74}, {php80, tc.php80}} {
+			result, err := c.runtime.Compare(tc.a, tc.b)
+			if err != nil {
+				t.Errorf("Compare(%#v, %#v) on PHP %d returned error: %v", tc.a, tc.b, c.runtime.Version, err)
+			} else if result != c.expected {
+				t.Errorf("Compare(%#v, %#v) on PHP %d = %d; want %d", tc.a, tc.b, c.runtime.Version, result, c.expected)
+			}
+		}
+	}
+}
+
+func TestCompareErrors(t *testing.T) {
+	recursive := []any{nil}
+	recursive[0] = recursive
+
+	testCases := []struct {
+		a, b     any
+		expected string
+	}{
+		{1, uint(1), "unsupported type : uint"},
+		{complex(1, 2), 1, "unsupported type : complex128"},
+		{[]any{1}, []any{make(chan int)}, "unsupported type : chan int"},
+		{recursive, recursive, "Nesting level too deep - recursive dependency?"},
+	}
+
+	for _, tc := range testCases {
+		_, err := Compare(tc.a, tc.b)
+		if err == nil || err.Error() != tc.expected {
+			t.Errorf("Compare(%T, %T) returned error %v; want %q", tc.a, tc.b, err, tc.expected)
+		}
+	}
+}
+
+func TestLooseEquals(t *testing.T) {
+	testCases := []struct {
+		a, b  any
+		php56 bool
+		php80 bool
+	}{
+		{math.NaN(), math.NaN(), false, false},
+		{math.NaN(), 0, false, false},
+		{1, 1.0, true, true},
+		{0, "abc", true, false},
+		{"abc", 0, true, false},
+		{"1", "01", true, true},
+		{"10", "1e1", true, true},
+		{100, "1e2", true, true},
+		{"abc", "ABC", false, false},
+		{nil, false, true, true},
+		{[]int{1, 2}, []string{"1", "2"}, true, true},
+		{Point{1, 2}, Point{1, 2}, true, true},
+		{Point{1, 2}, Sample{}, false, false},
+	}
+
+	php56, php80 := NewRuntime(PHP56), NewRuntime(PHP80)
+	for _, tc := range testCases {
+		if result, err := php56.LooseEquals(tc.a, tc.b); err != nil || result != tc.php56 {
+			t.Errorf("LooseEquals(%#v, %#v) on PHP 5.6 = %v, %v; want %v", tc.a, tc.b, result, err, tc.php56)
+		}
+		if result, err := php80.LooseEquals(tc.a, tc.b); err != nil || result != tc.php80 {
+			t.Errorf("LooseEquals(%#v, %#v) on PHP 8.0 = %v, %v; want %v", tc.a, tc.b, result, err, tc.php80)
+		}
+	}
+}
+
+func ExampleIdentical() {
+	fmt.Println(Identical(1, int64(1)))
+	fmt.Println(Identical(1, 1.0))
+	fmt.Println(Identical("1", 1))
+	fmt.Println(Identical(ParseStr("a[]=1&a[]=2"), omap("a", omap(0, "1", 1, "2"))))
+	fmt.Println(Identical(omap("a", 1, "b", 2), omap("b", 2, "a", 1)))
+	// Output:
+	// true
+	// false
+	// false
+	// true
+	// false
+}
+
+// TestIdentical tests Identical function.
+//
+// References:
+//   - https://github.com/php/php-src/blob/php-5.6.40/tests/lang/operators/comparison_identical_basiclong_64bit.phpt
+//   - https://github.com/php/php-src/blob/php-8.3.0/Zend/tests/identical_arrays.phpt
+func TestIdentical(t *testing.T) {
+	bird := &Bird{"tweety"}
+	file := getFile()
+	defer file.Close()
+
+	testCases := []struct {
+		a, b     any
+		expected bool
+	}{
+		{nil, nil, true},
+		{nil, false, false},
+		{false, false, true},
+		{true, 1, false},
+		{1, 1, true},
+		{int8(1), int64(1), true},
+		{1, 2, false},
+		{1, 1.0, false},
+		{1.5, float32(1.5), true},
+		{math.NaN(), math.NaN(), false},
+		{"1", 1, false},
+		{"abc", "abc", true},
+		{"1e3", "1000", false},
+		{"", nil, false},
+		{file, file, true},
+		{file, getFile(), false},
+
+		{[]int{}, map[string]int{}, true},
+		{[]int{1, 2}, []int{1, 2}, true},
+		{[]int{1, 2}, []int64{1, 2}, true},
+		{[]int{1, 2}, []any{1, "2"}, false},
+		{[]int{1, 2}, []int{2, 1}, false},
+		{[]int{1, 2}, map[int]int{1: 2, 0: 1}, true},
+		{[]string{"a"}, map[string]string{"0": "a"}, true},
+		{omap(0, "a", 1, "b"), omap(1, "b", 0, "a"), false},
+		{omap("0", "a"), omap(0, "a"), true},
+		{omap("a", omap(0, "x")), omap("a", omap(0, "x")), true},
+		{omap("a", omap(0, "x")), omap("a", &[]string{"x"}), false},
+		{omap("a", []string{"x"}), omap("a", [1]string{"x"}), true},
+
+		{Point{1, 2}, Point{1, 2}, true},
+		{Point{1, 2}, Point{2, 1}, false},
+		{Point{1, 2}, &Point{1, 2}, false},
+		{&Point{1, 2}, &Point{1, 2}, false},
+		{bird, bird, true},
+		{bird, &Bird{"tweety"}, false},
+		{temperature{1.5, "C"}, temperature{1.5, "C"}, true},
+		{temperature{1.5, "C"}, temperature{1.5, "F"}, false},
+		{Sample{}, Sample2{}, false},
+		{Sample{}, "sample object", false},
+
+		{uint(1), uint(1), false},
+	}
+
+	for _, tc := range testCases {
+		if result := Identical(tc.a, tc.b); result != tc.expected {
+			t.Errorf("Identical(%#v, %#v) = %v; want %v", tc.a, tc.b, result, tc.expected)
+		}
+	}
+}
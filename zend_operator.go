@@ -1,32 +1,231 @@
 package gophplib
 
 import (
-	"database/sql"
 	"fmt"
 	"math"
-	"net"
-	"os"
 	"reflect"
+	"strings"
 )
 
-type toStringAble interface {
-	toString() string
+// Stringable is implemented by any value which can be converted to a string,
+// just like PHP objects implementing the __toString magic method. It is named
+// after PHP 8's Stringable interface.
+//
+// ToString may be declared with either a value receiver or a pointer receiver.
+// Functions of this package honor both of them regardless of whether a struct
+// value or a pointer to it is given.
+//
+// Reference:
+//   - https://www.php.net/manual/en/language.oop5.magic.php#object.tostring
+//   - https://www.php.net/manual/en/class.stringable.php
+type Stringable interface {
+	ToString() string
+}
+
+// FromStringer wraps the given fmt.Stringer so that its String method is used as
+// PHP's __toString magic method.
+//
+// Values implementing fmt.Stringer are not considered as Stringable by default,
+// because many Go types implement fmt.Stringer although PHP would never treat
+// their counterparts as objects with __toString. (e.g. time.Duration is an
+// integer, and net.IP is an array in PHP's point of view) Use this function to
+// opt in to fmt.Stringer explicitly.
+func FromStringer(s fmt.Stringer) Stringable {
+	return stringerObject{s}
+}
+
+type stringerObject struct {
+	fmt.Stringer
+}
+
+func (s stringerObject) ToString() string {
+	return s.String()
+}
+
+// asStringable returns the Stringable implementation of the given value, if
+// any. See asImplementation for details.
+func asStringable(value any) (Stringable, bool) {
+	return asImplementation[Stringable](value)
+}
+
+// asImplementation returns the implementation of interface T of the given
+// value, if any. Unlike a plain type assertion, it also finds methods declared
+// with a pointer receiver when a struct value is given, by calling the method on
+// a copy of the value.
+//
+// A nil pointer is treated as an object without any implementation, even if
+// its type implements T, so that methods are never called on nil.
+func asImplementation[T any](value any) (T, bool) {
+	var zero T
+	v := reflect.ValueOf(value)
+	if v.Kind() == reflect.Pointer && v.IsNil() {
+		return zero, false
+	}
+	if s, ok := value.(T); ok {
+		return s, true
+	}
+	iface := reflect.TypeOf((*T)(nil)).Elem()
+	if v.Kind() != reflect.Struct || !reflect.PointerTo(v.Type()).Implements(iface) {
+		return zero, false
+	}
+	ptr := reflect.New(v.Type())
+	ptr.Elem().Set(v)
+	return ptr.Interface().(T), true
+}
+
+// BoolCastable is implemented by objects which override how they are converted
+// to bool, just like PHP extension classes overriding the cast_object handler.
+// For example, PHP's SimpleXMLElement is converted to false if it is an empty
+// element, while the other objects are always converted to true.
+//
+// Like Stringable, ToBool may be declared with either a value receiver or a
+// pointer receiver.
+//
+// Reference:
+//   - https://github.com/php/php-src/blob/php-5.6.40/ext/simplexml/simplexml.c
+type BoolCastable interface {
+	ToBool() bool
+}
+
+// isObject reports whether the given value is treated as a PHP object, which is
+// either a struct or a pointer to a struct.
+func isObject(value any) bool {
+	t := reflect.TypeOf(value)
+	if t == nil {
+		return false
+	}
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct
+}
+
+// className returns the PHP class name of the given object, which is the name of
+// its Go type without package path.
+func className(value any) string {
+	t := reflect.TypeOf(value)
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Name() == "" {
+		return t.String()
+	}
+	return t.Name()
+}
+
+// objectProperty is a property of a PHP object, which is a field of a struct.
+type objectProperty struct {
+	// name is the name of the field.
+	name string
+	// public reports whether the field is exported. Unexported fields are
+	// treated as private properties.
+	public bool
+	value  any
+}
+
+// objectProperties returns the properties of the given object in the order of
+// the fields of the struct. Objects which are not structs or pointers to
+// structs, and nil pointers have no properties.
+func objectProperties(value any) []objectProperty {
+	v := reflect.Indirect(reflect.ValueOf(value))
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+	t := v.Type()
+	props := make([]objectProperty, t.NumField())
+	for i := range props {
+		f := t.Field(i)
+		props[i] = objectProperty{name: f.Name, public: f.IsExported(), value: readValue(v.Field(i))}
+	}
+	return props
+}
+
+// zvalType is the type of a value in PHP's point of view, which is the same as
+// the type of PHP's zval.
+type zvalType uint8
+
+const (
+	// typeUnsupported is the type of values which can not be represented in PHP.
+	typeUnsupported zvalType = iota
+	typeNull
+	typeBool
+	typeLong
+	typeDouble
+	typeString
+	typeArray
+	typeObject
+	typeResource
+)
+
+// zvalTypeOf returns the type of the given value in PHP's point of view.
+//   - nil is null.
+//   - int, int8, int16, int32 and int64 are integers.
+//   - float32 and float64 are floats.
+//   - *os.File, *net.Conn, *sql.DB and the types registered with RegisterResourceType are resources.
+//   - Arrays, slices, maps, ordered maps and Array are arrays, unless they implement Stringable.
+//   - Structs, pointers to structs and any types which implement Stringable are objects.
+func zvalTypeOf(value any) zvalType {
+	switch value.(type) {
+	case nil:
+		return typeNull
+	case bool:
+		return typeBool
+	case int, int8, int16, int32, int64:
+		return typeLong
+	case float32, float64:
+		return typeDouble
+	case string:
+		return typeString
+	}
+	if _, ok := resourceTypeOf(value); ok {
+		return typeResource
+	}
+	if _, ok := asStringable(value); ok {
+		return typeObject
+	}
+	if isCollectionType(value) {
+		return typeArray
+	}
+	if isObject(value) {
+		return typeObject
+	}
+	return typeUnsupported
+}
+
+// longOf returns the value of the given integer as int64. It expects the
+// value's zvalType to be typeLong.
+func longOf(value any) int64 {
+	return reflect.ValueOf(value).Int()
+}
+
+// doubleOf returns the value of the given integer or float as float64. It
+// expects the value's zvalType to be either typeLong or typeDouble.
+func doubleOf(value any) float64 {
+	switch v := value.(type) {
+	case float32:
+		return float64(v)
+	case float64:
+		return v
+	default:
+		return float64(longOf(value))
+	}
 }
 
-// floatToString converts a float64 to a string based on the PHP 5.6 rules.
-//   - Allows up to a maximum of 14 digits, including both integer and decimal places.
+// floatToString converts a float64 to a string like PHP's string conversion.
+// It uses the "precision" INI setting as the number of significant digits.
 //   - Remove trailing zeros from the fractional part
 //     ex) 123.4000 → "123.4"
-//   - Keep the values as is if the last digit is not 0.
-//     ex) 123.45 → "123.45"
-//   - If the integer part exceeds 14 digits, use exponential notation.
-//     ex) 123456789123456.40 → "1.2345678901234e+14"
-//   - If the total number of digits exceeds 14, truncate the decimal places.
-//     ex) 123.45678901234 → "123.4567890123"
+//   - If the integer part exceeds the precision, use exponential notation.
+//     ex) 123456789123456.40 → "1.2345678912346E+14"
+//   - If the total number of digits exceeds the precision, round the decimal places.
+//     ex) 123.456789012345 → "123.45678901235"
+//   - Use at least one digit after the decimal point in exponential notation.
+//     ex) 1e25 → "1.0E+25"
 //
 // Reference :
 //   - https://github.com/php/php-src/blob/php-5.6.40/Zend/zend_operators.c#L627-L633
-func floatToString(f64 float64) string {
+//   - https://github.com/php/php-src/blob/php-8.3.0/Zend/zend_operators.c
+func (r *Runtime) floatToString(f64 float64) string {
 	if math.IsNaN(f64) {
 		return "NAN"
 	}
@@ -36,17 +235,24 @@ func floatToString(f64 float64) string {
 	if math.IsInf(f64, -1) {
 		return "-INF"
 	}
-	return fmt.Sprintf("%.*G", 14, f64)
+	return string(smartStrAppendDouble(nil, f64, r.ini().Precision, false))
 }
 
-// ConvertToString attempts to convert the given value to string, emulating PHP 5.6'S _convert_to_string behavior.
-// Unlike PHP, which has built-in support for managing resource IDs for types like files and database connections,
-// Go does not inherently manage resource IDs. Due to this language difference, this function uses the values' pointer
-// address as the pseudo resource ID for identifiable resource types.
+// ConvertToString attempts to convert the given value to string, emulating PHP's _convert_to_string behavior.
+// Floats are converted using the "precision" INI setting of the Runtime. Arrays are converted to "Array", and
+// "Array to string conversion" is emitted to the diagnostic handler of the Runtime, as E_NOTICE before PHP 8 and
+// as E_WARNING since PHP 8.
+// Resources are converted to "Resource id #N", where N is the resource ID assigned by the ResourceRegistry of the
+// Runtime. IDs are assigned sequentially to resources in the order they are seen for the first time, so the
+// result is the same as PHP's if resources are converted in the same order as they are opened in PHP. Note that
+// the package-level functions share process-global IDs. See DefaultResourceRegistry.
 //
 // This function returns error if given argument is not one of following:
 // string, int, int8, int16, int32, int64, float32, float64, bool, nil, *os.File, *net.Conn, and *sql.DB,
-// array, slice, map and any type which does not implement interface { toString() string }.
+// the types registered with RegisterResourceType, array, slice, map, orderedmap.OrderedMap and any type which
+// implements Stringable.
+// For structs and pointers to structs which do not implement Stringable, the returned error has
+// the same message with PHP's, e.g. "Object of class Dog could not be converted to string".
 //
 // NOTE : If the given argument's type is float32, it will be converted to float64 internally.
 // However, converting float32 to float64 may lead to precision loss.
@@ -57,7 +263,7 @@ func floatToString(f64 float64) string {
 //     https://github.com/php/php-src/blob/php-5.6.40/Zend/zend_operators.c#L593-L661
 //   - convert_object_to_type implementation:
 //     https://github.com/php/php-src/blob/php-5.6.40/Zend/zend_operators.c#L333-L357
-func ConvertToString(value any) (string, error) {
+func (r *Runtime) ConvertToString(value any) (string, error) {
 	if value == nil {
 		return "", nil
 	}
@@ -75,24 +281,762 @@ func ConvertToString(value any) (string, error) {
 	case int, int8, int16, int32, int64:
 		return fmt.Sprintf("%d", v), nil
 	case float32:
-		return floatToString(float64(v)), nil
+		return r.floatToString(float64(v)), nil
 	case float64:
-		return floatToString(v), nil
-	// check for special types such as a pointer of file, network, database resources
-	case *os.File, *net.Conn, *sql.DB:
-		// using a resource's address as the resource ID
-		return fmt.Sprintf("Resource id %p", v), nil
-	}
-	// use reflection to handle array, slice, map types
-	t := reflect.ValueOf(value).Kind()
-	if t == reflect.Array || t == reflect.Slice || t == reflect.Map {
+		return r.floatToString(v), nil
+	}
+	if id, ok := r.resourceID(value); ok {
+		return fmt.Sprintf("Resource id #%d", id), nil
+	}
+	if s, ok := asStringable(value); ok {
+		return s.ToString(), nil
+	}
+	// handle array, slice, map and ordered map types
+	if isCollectionType(value) {
+		r.emit(r.noticeOrWarning(), "Array to string conversion")
 		return "Array", nil
 	}
-	if t == reflect.Struct {
-		if result, ok := value.(toStringAble); ok {
-			return result.toString(), nil
-		}
+	if isObject(value) {
+		return "", &Error{Message: fmt.Sprintf("Object of class %s could not be converted to string", className(value))}
 	}
 	// return an error for unsupported types.
-	return "", fmt.Errorf("unsupported type : %T", value)
+	return "", &UnsupportedTypeError{value}
+}
+
+// ConvertToString works the same as Runtime.ConvertToString of a Runtime emulating PHP 5.6.
+func ConvertToString(value any) (string, error) {
+	return defaultRuntime.ConvertToString(value)
+}
+
+// ConvertToLong attempts to convert the given value to int64, emulating PHP's convert_to_long behavior. It is
+// what PHP does for (int) casts and intval() with base 10.
+//
+// The conversion rules are as follows:
+//   - nil and false become 0, and true becomes 1.
+//   - Floats are truncated toward zero. Floats out of range of int64 wrap around modulo 2^64, just like PHP on
+//     64-bit platforms. NaN and infinities become math.MinInt64 on PHP 5.6, and 0 on PHP 7 and later.
+//   - Before PHP 7.1, strings are parsed like C's strtol with base 10. Leading whitespaces and a sign are
+//     allowed, and parsing stops at the first non-digit character, so "12abc" becomes 12 and "1e3" becomes 1.
+//     Since PHP 7.1, strings are parsed as numeric strings, so "1e3" becomes 1000 and "1.9e1abc" becomes 19.
+//     Hexadecimal strings such as "0x1A" become 0. Integers out of range of int64 are saturated to
+//     math.MaxInt64 or math.MinInt64.
+//   - Arrays, slices, maps and ordered maps become 1 if they have any element, otherwise 0.
+//   - Objects become 1, even if they implement Stringable. "Object of class X could not be converted to int"
+//     is emitted to the diagnostic handler of the Runtime in this case, like PHP does.
+//   - Resources become their resource ID assigned by the ResourceRegistry of the Runtime. See ConvertToString
+//     for details.
+//
+// This function returns error if given argument is not one of types described above.
+//
+// Reference:
+//   - convert_to_long_base implementation:
+//     https://github.com/php/php-src/blob/php-5.6.40/Zend/zend_operators.c
+//     https://github.com/php/php-src/blob/php-7.1.0/Zend/zend_operators.c
+//   - zend_dval_to_lval implementation:
+//     https://github.com/php/php-src/blob/php-5.6.40/Zend/zend_operators.h
+//     https://github.com/php/php-src/blob/php-7.0.0/Zend/zend_operators.h
+func (r *Runtime) ConvertToLong(value any) (int64, error) {
+	switch v := value.(type) {
+	case nil:
+		return 0, nil
+	case bool:
+		if v {
+			return 1, nil
+		}
+		return 0, nil
+	case int:
+		return int64(v), nil
+	case int8:
+		return int64(v), nil
+	case int16:
+		return int64(v), nil
+	case int32:
+		return int64(v), nil
+	case int64:
+		return v, nil
+	case float32:
+		return r.dvalToLval(float64(v)), nil
+	case float64:
+		return r.dvalToLval(v), nil
+	case string:
+		if r.Version < PHP71 {
+			return strtol(v, 10), nil
+		}
+		ns := r.IsNumericString(v, AllowErrorsSilently)
+		switch ns.Type {
+		case NumericLong:
+			return ns.Long, nil
+		case NumericDouble:
+			return zendDvalToLvalCap(ns.Double), nil
+		default:
+			return 0, nil
+		}
+	}
+	if id, ok := r.resourceID(value); ok {
+		return int64(id), nil
+	}
+	if isCollectionType(value) {
+		if countElements(value) > 0 {
+			return 1, nil
+		}
+		return 0, nil
+	}
+	if isObject(value) {
+		r.emit(r.noticeOrWarning(), "Object of class %s could not be converted to int", className(value))
+		return 1, nil
+	}
+	return 0, &UnsupportedTypeError{value}
+}
+
+// ConvertToLong works the same as Runtime.ConvertToLong of a Runtime emulating PHP 5.6.
+func ConvertToLong(value any) (int64, error) {
+	return defaultRuntime.ConvertToLong(value)
+}
+
+const (
+	twoPow63 = float64(1 << 63)
+	twoPow64 = twoPow63 * 2
+)
+
+// zendDvalToLval is a ported function that works exactly the same as PHP 5.6's
+// zend_dval_to_lval function on 64-bit platforms. Floats out of range of int64
+// wrap around modulo 2^64.
+//
+// Reference:
+//   - https://github.com/php/php-src/blob/php-5.6.40/Zend/zend_operators.h
+func zendDvalToLval(d float64) int64 {
+	// >= as (double)LONG_MAX is outside signed range
+	if d >= twoPow63 || d < -twoPow63 {
+		dmod := math.Mod(d, twoPow64)
+		if dmod < 0 {
+			dmod += twoPow64
+		}
+		if dmod > twoPow63 {
+			dmod -= twoPow64
+		}
+		return castDoubleToLong(dmod)
+	}
+	return castDoubleToLong(d)
+}
+
+// dvalToLval converts a float64 to int64 like zend_dval_to_lval function of the
+// emulated PHP version. PHP 7 and later convert NaN and infinity to 0, while
+// PHP 5.6 converts them to math.MinInt64.
+//
+// Reference:
+//   - https://github.com/php/php-src/blob/php-7.0.0/Zend/zend_operators.h
+func (r *Runtime) dvalToLval(d float64) int64 {
+	if r.Version >= PHP70 && (math.IsNaN(d) || math.IsInf(d, 0)) {
+		return 0
+	}
+	return zendDvalToLval(d)
+}
+
+// zendDvalToLvalCap is a ported function that works exactly the same as PHP
+// 7's zend_dval_to_lval_cap function, which saturates out of range floats
+// instead of wrapping around. It is used for converting numeric strings to
+// integers since PHP 7.1.
+//
+// Reference:
+//   - https://github.com/php/php-src/blob/php-7.1.0/Zend/zend_operators.h
+func zendDvalToLvalCap(d float64) int64 {
+	if math.IsNaN(d) || math.IsInf(d, 0) {
+		return 0
+	}
+	if d >= twoPow63 || d < -twoPow63 {
+		if d > 0 {
+			return math.MaxInt64
+		}
+		return math.MinInt64
+	}
+	return int64(d)
+}
+
+// castDoubleToLong converts a float64 to int64 like C's (long) cast on x86-64.
+// Go leaves out of range conversions implementation-specific, while x86-64's
+// cvttsd2si instruction always returns math.MinInt64 for NaN and out of range
+// values.
+func castDoubleToLong(d float64) int64 {
+	if math.IsNaN(d) || d >= twoPow63 || d < -twoPow63 {
+		return math.MinInt64
+	}
+	return int64(d)
+}
+
+// strtol is a ported function that works exactly the same as C's strtol
+// function with base 10 or 16, on platforms where long is 64-bit.
+//
+// References:
+//   - https://en.cppreference.com/w/c/string/byte/strtol
+func strtol(s string, base int) int64 {
+	i := 0
+	for i < len(s) && isAsciiWhitespace(s[i]) {
+		i++
+	}
+	negative := false
+	if i < len(s) && (s[i] == '+' || s[i] == '-') {
+		negative = s[i] == '-'
+		i++
+	}
+	if base == 16 && i+2 < len(s) && s[i] == '0' && (s[i+1] == 'x' || s[i+1] == 'X') && isxdigit(s[i+2]) {
+		i += 2
+	}
+
+	// Accumulate as negative number, since the magnitude of math.MinInt64 is
+	// larger than math.MaxInt64.
+	var acc int64
+	overflow := false
+loop:
+	for ; i < len(s); i++ {
+		var digit int64
+		switch c := s[i]; {
+		case isdigit(c):
+			digit = int64(c - '0')
+		case base == 16 && isxdigit(c):
+			digit = int64(htoi('0', c))
+		default:
+			break loop
+		}
+		if acc < (math.MinInt64+digit)/int64(base) {
+			overflow = true
+			continue
+		}
+		acc = acc*int64(base) - digit
+	}
+
+	switch {
+	case overflow && negative:
+		return math.MinInt64
+	case overflow, !negative && acc == math.MinInt64:
+		return math.MaxInt64
+	case negative:
+		return acc
+	default:
+		return -acc
+	}
+}
+
+// ConvertToDouble attempts to convert the given value to float64, emulating PHP's convert_to_double behavior. It
+// is what PHP does for (float) casts and floatval().
+//
+// The conversion rules are as follows:
+//   - nil and false become 0, and true becomes 1.
+//   - Integers are converted to the nearest float64.
+//   - Strings are parsed like PHP's zend_strtod. Leading whitespaces are skipped and trailing garbage is ignored,
+//     so " 1.5e3xyz" becomes 1500. Strings which do not start with a decimal number become 0, including "inf",
+//     "nan" and hexadecimal floats, which are accepted by strconv.ParseFloat.
+//   - Arrays, slices, maps and ordered maps become 1 if they have any element, otherwise 0.
+//   - Objects become 1, even if they implement Stringable. "Object of class X could not be converted to
+//     double" is emitted to the diagnostic handler of the Runtime in this case, like PHP does. PHP 8 says
+//     "float" instead of "double".
+//   - Resources become their resource ID assigned by the ResourceRegistry of the Runtime. See ConvertToString
+//     for details.
+//
+// This function returns error if given argument is not one of types described above.
+//
+// Reference:
+//   - convert_to_double implementation:
+//     https://github.com/php/php-src/blob/php-5.6.40/Zend/zend_operators.c
+//     https://github.com/php/php-src/blob/php-8.3.0/Zend/zend_operators.c
+func (r *Runtime) ConvertToDouble(value any) (float64, error) {
+	switch v := value.(type) {
+	case float32:
+		return float64(v), nil
+	case float64:
+		return v, nil
+	case string:
+		f, _ := zendStrtod(v)
+		return f, nil
+	}
+	if zvalTypeOf(value) == typeObject && isObject(value) {
+		typ := "double"
+		if r.Version >= PHP80 {
+			typ = "float"
+		}
+		r.emit(r.noticeOrWarning(), "Object of class %s could not be converted to %s", className(value), typ)
+		return 1, nil
+	}
+	// All the other types are converted to double in the same way as long
+	l, err := r.ConvertToLong(value)
+	if err != nil {
+		return 0, err
+	}
+	return float64(l), nil
+}
+
+// ConvertToDouble works the same as Runtime.ConvertToDouble of a Runtime emulating PHP 5.6.
+func ConvertToDouble(value any) (float64, error) {
+	return defaultRuntime.ConvertToDouble(value)
+}
+
+// ConvertToBool converts the given value to bool, emulating PHP's convert_to_boolean behavior. It is what PHP does
+// for (bool) casts, boolval() and conditions like if ($x). The result is the same for all the PHP versions.
+//
+// The conversion rules are as follows:
+//   - nil is false.
+//   - Integers are false if they are 0, otherwise true.
+//   - Floats are false if they are 0 or -0, otherwise true. NaN is true.
+//   - Strings are false if they are "" or "0", otherwise true. Note that "0.0", " " and "00" are true.
+//   - Arrays, slices, maps and ordered maps are false if they have no element, otherwise true.
+//   - Objects implementing BoolCastable are converted using their ToBool method.
+//   - Any other values, including objects (even if they implement Stringable) and resources, are true.
+//
+// Reference:
+//   - convert_to_boolean implementation:
+//     https://github.com/php/php-src/blob/php-5.6.40/Zend/zend_operators.c
+//   - zend_std_cast_object_tostring implementation:
+//     https://github.com/php/php-src/blob/php-5.6.40/Zend/zend_object_handlers.c
+func (r *Runtime) ConvertToBool(value any) bool {
+	switch v := value.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case int:
+		return v != 0
+	case int8:
+		return v != 0
+	case int16:
+		return v != 0
+	case int32:
+		return v != 0
+	case int64:
+		return v != 0
+	case float32:
+		return v != 0
+	case float64:
+		return v != 0
+	case string:
+		return v != "" && v != "0"
+	}
+	if isCollectionType(value) {
+		return countElements(value) > 0
+	}
+	if b, ok := asImplementation[BoolCastable](value); ok {
+		return b.ToBool()
+	}
+	return true
+}
+
+// ConvertToBool works the same as Runtime.ConvertToBool of a Runtime emulating PHP 5.6.
+func ConvertToBool(value any) bool {
+	return defaultRuntime.ConvertToBool(value)
+}
+
+// NumericType is the type of the number represented by a numeric string.
+type NumericType uint8
+
+const (
+	// NotNumeric means that the string is not numeric.
+	NotNumeric NumericType = iota
+	// NumericLong means that the string represents an integer. It is the same
+	// as PHP's IS_LONG.
+	NumericLong
+	// NumericDouble means that the string represents a float. It is the same as
+	// PHP's IS_DOUBLE.
+	NumericDouble
+)
+
+// AllowErrors specifies whether trailing data of numeric strings are tolerated.
+// It is the same as allow_errors parameter of PHP's _is_numeric_string_ex
+// function.
+type AllowErrors int
+
+const (
+	// DisallowErrors makes strings with trailing data non-numeric.
+	DisallowErrors AllowErrors = 0
+	// AllowErrorsSilently makes strings with trailing data numeric, using their
+	// leading numeric part. (ex: "123abc" is parsed as 123)
+	AllowErrorsSilently AllowErrors = 1
+	// AllowErrorsWithNotice is the same as AllowErrorsSilently, but PHP 5.6 and
+	// PHP 7 emit E_NOTICE "A non well formed numeric value encountered" to the
+	// diagnostic handler in this case. PHP 8 treats it the same as
+	// AllowErrorsSilently, and leaves it to the caller to emit a diagnostic
+	// using the TrailingData field.
+	AllowErrorsWithNotice AllowErrors = -1
+)
+
+// NumericString is the result of IsNumericString.
+type NumericString struct {
+	// Type is the type of the number represented by the string.
+	Type NumericType
+	// Long is the value of the number if Type is NumericLong.
+	Long int64
+	// Double is the value of the number if Type is NumericDouble.
+	Double float64
+	// Overflow is 1 or -1 if the string is an integer which overflows int64 in
+	// positive or negative direction respectively, otherwise 0. In that case,
+	// Type is NumericDouble. It is the same as oflow_info of PHP.
+	Overflow int
+	// TrailingData reports whether the string has trailing data which was
+	// tolerated by AllowErrors.
+	TrailingData bool
+}
+
+const (
+	// maxLengthOfLong is PHP's MAX_LENGTH_OF_LONG on 64-bit platforms.
+	maxLengthOfLong = 20
+	// longMinDigits is PHP's long_min_digits on 64-bit platforms.
+	longMinDigits = "9223372036854775808"
+)
+
+// IsNumericString is a ported function that works exactly the same as PHP's _is_numeric_string_ex function. It
+// reports whether the given string is numeric, and parses it as either int64 or float64.
+//
+// A numeric string consists of optional leading whitespaces, an optional sign, and a decimal integer or float
+// with an optional exponent. (ex: " -1.5e3") Integers which overflow int64 are parsed as float64. Trailing data
+// are tolerated only if allowErrors is not DisallowErrors.
+//
+// Behaviors which differ between PHP versions are as follows:
+//   - PHP 5.6 accepts hexadecimal integers like "0x1A", while PHP 7+ does not.
+//   - PHP 8+ accepts trailing whitespaces like "123 ", while PHP 5.6 and PHP 7 treat them as trailing data.
+//
+// Unlike the original function, it always parses the numbers as if both lval and dval arguments were given.
+//
+// References:
+//   - https://github.com/php/php-src/blob/php-5.6.40/Zend/zend_operators.h
+//   - https://github.com/php/php-src/blob/php-7.4.33/Zend/zend_operators.c
+//   - https://github.com/php/php-src/blob/php-8.3.0/Zend/zend_operators.c
+func (r *Runtime) IsNumericString(str string, allowErrors AllowErrors) NumericString {
+	var ret NumericString
+
+	// Skip any whitespace
+	for len(str) > 0 && isAsciiWhitespace(str[0]) {
+		str = str[1:]
+	}
+
+	// at emulates reading a NUL-terminated C string
+	at := func(i int) byte {
+		if i < len(str) {
+			return str[i]
+		}
+		return 0
+	}
+
+	ptr := 0
+	if at(ptr) == '-' || at(ptr) == '+' {
+		ptr++
+	}
+
+	base, digits, dpOrE := 10, 0, 0
+	processDouble := false
+	if isdigit(at(ptr)) {
+		// Handle hex strings, which are removed in PHP 7
+		if r.Version < PHP70 && len(str) > 2 && str[0] == '0' && (str[1] == 'x' || str[1] == 'X') {
+			base = 16
+			ptr += 2
+		}
+
+		// Skip any leading 0s
+		for at(ptr) == '0' {
+			ptr++
+		}
+
+		// Count the number of digits. If a decimal point or an exponent is
+		// encountered, parse it as a double.
+		ret.Type = NumericLong
+	loop:
+		for ; digits < maxLengthOfLong; digits, ptr = digits+1, ptr+1 {
+			c := at(ptr)
+			switch {
+			case isdigit(c) || base == 16 && isxdigit(c):
+				continue
+			case base == 10 && c == '.' && dpOrE < 1:
+				processDouble = true
+			case base == 10 && (c == 'e' || c == 'E') && dpOrE == 0:
+				e := ptr + 1
+				if at(e) == '-' || at(e) == '+' {
+					ptr = e
+					e++
+				}
+				processDouble = isdigit(at(e))
+			}
+			break loop
+		}
+
+		if !processDouble {
+			if base == 10 {
+				if digits >= maxLengthOfLong {
+					ret.Overflow = oflowInfo(str)
+					processDouble = true
+				}
+			} else if !(digits < 16 || digits == 16 && str[ptr-digits] <= '7') {
+				ret.Double, ptr = zendHexStrtod(str)
+				ret.Overflow = 1
+				ret.Type = NumericDouble
+			}
+		}
+	} else if at(ptr) == '.' && isdigit(at(ptr+1)) {
+		processDouble = true
+	} else {
+		return NumericString{}
+	}
+
+	if processDouble {
+		ret.Type = NumericDouble
+		ret.Double, ptr = zendStrtod(str)
+	}
+
+	if ptr != len(str) {
+		trailing := str[ptr:]
+		// Trailing whitespaces are allowed since PHP 8
+		if r.Version >= PHP80 {
+			for len(trailing) > 0 && isAsciiWhitespace(trailing[0]) {
+				trailing = trailing[1:]
+			}
+		}
+		if trailing != "" {
+			if allowErrors == DisallowErrors {
+				return NumericString{}
+			}
+			if allowErrors == AllowErrorsWithNotice && r.Version < PHP80 {
+				r.emit(ENotice, "A non well formed numeric value encountered")
+			}
+			ret.TrailingData = true
+		}
+	}
+
+	if ret.Type == NumericLong {
+		if digits == maxLengthOfLong-1 {
+			cmp := strings.Compare(cstring(str[ptr-digits:]), longMinDigits)
+			if !(cmp < 0 || cmp == 0 && str[0] == '-') {
+				ret.Double, _ = zendStrtod(str)
+				ret.Overflow = oflowInfo(str)
+				ret.Type = NumericDouble
+				return ret
+			}
+		}
+		ret.Long = strtol(str, base)
+	}
+	return ret
+}
+
+// IsNumericString works the same as Runtime.IsNumericString of a Runtime emulating PHP 5.6.
+func IsNumericString(str string, allowErrors AllowErrors) NumericString {
+	return defaultRuntime.IsNumericString(str, allowErrors)
+}
+
+// oflowInfo returns the direction of overflow of the given integer string.
+func oflowInfo(str string) int {
+	if str[0] == '-' {
+		return -1
+	}
+	return 1
+}
+
+// cstring returns the given string truncated at the first NUL byte, which is how
+// C functions see PHP strings.
+func cstring(s string) string {
+	if i := strings.IndexByte(s, 0); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
+
+// IsNumeric is a ported function that works exactly the same as PHP's is_numeric function. It reports whether
+// the given value is a number or a numeric string. For more information, see the [official PHP documentation].
+//
+// Integers and floats are always numeric. Strings are numeric if IsNumericString reports them as numeric
+// without trailing data. Values of any other types are not numeric.
+//
+// Reference:
+//   - https://github.com/php/php-src/blob/php-5.6.40/ext/standard/type.c
+//   - https://github.com/php/php-src/blob/php-8.3.0/ext/standard/type.c
+//
+// Test Cases:
+//   - https://github.com/php/php-src/blob/php-5.6.40/ext/standard/tests/general_functions/is_numeric.phpt
+//
+// [official PHP documentation]: https://www.php.net/manual/en/function.is-numeric.php
+func (r *Runtime) IsNumeric(value any) bool {
+	switch v := value.(type) {
+	case int, int8, int16, int32, int64, float32, float64:
+		return true
+	case string:
+		return r.IsNumericString(v, DisallowErrors).Type != NotNumeric
+	default:
+		return false
+	}
+}
+
+// IsNumeric works the same as Runtime.IsNumeric of a Runtime emulating PHP 5.6.
+func IsNumeric(value any) bool {
+	return defaultRuntime.IsNumeric(value)
+}
+
+// IncrementValue is a ported function that works exactly the same as PHP's increment_function, which implements
+// the ++ operator. It returns the incremented value instead of modifying the given value.
+//
+// The rules are as follows:
+//   - Integers are incremented as int64. math.MaxInt64 is incremented to float64.
+//   - Floats are incremented as float64.
+//   - nil is incremented to int64(1).
+//   - Numeric strings are converted to numbers and incremented.
+//   - Other strings are incremented in Perl style: the last alphanumeric character is incremented, carrying to
+//     the previous character as needed. (ex: "a" → "b", "Az" → "Ba", "zz" → "aaa", "a9" → "b0") Strings
+//     ending with a non-alphanumeric character are not changed, and an empty string is incremented to "1".
+//   - Any other values, including bool, arrays and objects, are returned as is.
+//
+// Reference:
+//   - https://github.com/php/php-src/blob/php-5.6.40/Zend/zend_operators.c
+//   - https://github.com/php/php-src/blob/php-8.3.0/Zend/zend_operators.c
+//
+// Test Cases:
+//   - https://github.com/php/php-src/blob/php-5.6.40/Zend/tests/increment_001.phpt
+func (r *Runtime) IncrementValue(value any) any {
+	switch zvalTypeOf(value) {
+	case typeLong:
+		l := longOf(value)
+		if l == math.MaxInt64 {
+			return float64(l) + 1
+		}
+		return l + 1
+	case typeDouble:
+		return doubleOf(value) + 1
+	case typeNull:
+		return int64(1)
+	case typeString:
+		s := value.(string)
+		ns := r.IsNumericString(s, DisallowErrors)
+		switch ns.Type {
+		case NumericLong:
+			if ns.Long == math.MaxInt64 {
+				// switch to double
+				return float64(ns.Long) + 1
+			}
+			return ns.Long + 1
+		case NumericDouble:
+			return ns.Double + 1
+		default:
+			// Perl style string increment
+			return incrementString(s)
+		}
+	default:
+		return value
+	}
+}
+
+// IncrementValue works the same as Runtime.IncrementValue of a Runtime emulating PHP 5.6.
+func IncrementValue(value any) any {
+	return defaultRuntime.IncrementValue(value)
+}
+
+// DecrementValue is a ported function that works exactly the same as PHP's decrement_function, which implements
+// the -- operator. It returns the decremented value instead of modifying the given value.
+//
+// The rules are as follows:
+//   - Integers are decremented as int64. math.MinInt64 is decremented to float64.
+//   - Floats are decremented as float64.
+//   - Numeric strings are converted to numbers and decremented, and an empty string is decremented to int64(-1).
+//   - Any other values, including nil, bool, non-numeric strings, arrays and objects, are returned as is. Note
+//     that unlike IncrementValue, there is no Perl style string decrement.
+//
+// Reference:
+//   - https://github.com/php/php-src/blob/php-5.6.40/Zend/zend_operators.c
+//   - https://github.com/php/php-src/blob/php-8.3.0/Zend/zend_operators.c
+func (r *Runtime) DecrementValue(value any) any {
+	switch zvalTypeOf(value) {
+	case typeLong:
+		l := longOf(value)
+		if l == math.MinInt64 {
+			return float64(l) - 1
+		}
+		return l - 1
+	case typeDouble:
+		return doubleOf(value) - 1
+	case typeString:
+		s := value.(string)
+		if s == "" {
+			// consider as 0
+			return int64(-1)
+		}
+		ns := r.IsNumericString(s, DisallowErrors)
+		switch ns.Type {
+		case NumericLong:
+			if ns.Long == math.MinInt64 {
+				return float64(ns.Long) - 1
+			}
+			return ns.Long - 1
+		case NumericDouble:
+			return ns.Double - 1
+		default:
+			return s
+		}
+	default:
+		return value
+	}
+}
+
+// DecrementValue works the same as Runtime.DecrementValue of a Runtime emulating PHP 5.6.
+func DecrementValue(value any) any {
+	return defaultRuntime.DecrementValue(value)
+}
+
+// incrementString is a ported function that works exactly the same as PHP's
+// increment_string function.
+//
+// Reference:
+//   - https://github.com/php/php-src/blob/php-5.6.40/Zend/zend_operators.c
+func incrementString(str string) string {
+	if str == "" {
+		return "1"
+	}
+
+	const (
+		lowerCase = iota + 1
+		upperCase
+		numeric
+	)
+
+	s := []byte(str)
+	carry := false
+	last := 0
+	for pos := len(s) - 1; pos >= 0; pos-- {
+		ch := s[pos]
+		if ch >= 'a' && ch <= 'z' {
+			if ch == 'z' {
+				s[pos] = 'a'
+				carry = true
+			} else {
+				s[pos]++
+				carry = false
+			}
+			last = lowerCase
+		} else if ch >= 'A' && ch <= 'Z' {
+			if ch == 'Z' {
+				s[pos] = 'A'
+				carry = true
+			} else {
+				s[pos]++
+				carry = false
+			}
+			last = upperCase
+		} else if ch >= '0' && ch <= '9' {
+			if ch == '9' {
+				s[pos] = '0'
+				carry = true
+			} else {
+				s[pos]++
+				carry = false
+			}
+			last = numeric
+		} else {
+			carry = false
+			break
+		}
+		if !carry {
+			break
+		}
+	}
+
+	if carry {
+		switch last {
+		case numeric:
+			return "1" + string(s)
+		case upperCase:
+			return "A" + string(s)
+		case lowerCase:
+			return "a" + string(s)
+		}
+	}
+	return string(s)
 }
@@ -2,9 +2,12 @@ package gophplib
 
 import (
 	"fmt"
+	"math"
 	"os"
 	"reflect"
 	"testing"
+
+	"github.com/elliotchance/orderedmap/v2"
 )
 
 type Sample struct{}
@@ -15,10 +18,26 @@ type CustomType struct {
 	value string
 }
 
-func (s Sample) toString() string {
+type Bird struct {
+	name string
+}
+
+type Color struct {
+	name string
+}
+
+func (s Sample) ToString() string {
 	return "sample object"
 }
 
+func (b *Bird) ToString() string {
+	return "bird named " + b.name
+}
+
+func (c Color) String() string {
+	return "color " + c.name
+}
+
 func getFile() *os.File {
 	file, osErr := os.Open("README.md")
 	if osErr != nil {
@@ -52,17 +71,23 @@ func ExampleConvertToString() {
 	fmt.Println(ConvertToString([]int{1, 2, 3}))
 	// Slice
 	fmt.Println(ConvertToString([2]int{1, 2}))
-	// Object has toString
+	// Object has ToString
 	fmt.Println(ConvertToString(Cat{
 		name: "nabi",
 		age:  3,
 	}))
-	// Object has no toString
+	// Object has no ToString
 	fmt.Println(ConvertToString(Dog{
 		name: "choco",
 		age:  5,
 	}))
 
+	// Object has ToString with pointer receiver
+	fmt.Println(ConvertToString(Bird{name: "tweety"}))
+	// fmt.Stringer is used only when wrapped explicitly
+	fmt.Println(ConvertToString(Color{name: "red"}))
+	fmt.Println(ConvertToString(FromStringer(Color{name: "red"})))
+
 	// Output:
 	//  <nil>
 	// Hello, World <nil>
@@ -77,11 +102,32 @@ func ExampleConvertToString() {
 	// Array <nil>
 	// Array <nil>
 	// name is nabi and 3 years old <nil>
-	//  unsupported type : gophplib.Dog
+	//  Object of class Dog could not be converted to string
+	// bird named tweety <nil>
+	//  Object of class Color could not be converted to string
+	// color red <nil>
+}
+
+func ExampleRuntime_ConvertToString() {
+	r := NewRuntime(PHP83)
+	fmt.Println(r.ConvertToString(0.1 + 0.7))
+	fmt.Println(r.ConvertToString(1e25))
+
+	r.INI.Precision = 17
+	fmt.Println(r.ConvertToString(0.1))
+
+	r.INI.Precision = -1
+	fmt.Println(r.ConvertToString(0.1))
+	// Output:
+	// 0.8 <nil>
+	// 1.0E+25 <nil>
+	// 0.10000000000000001 <nil>
+	// 0.1 <nil>
 }
 
 func TestConvertToString(t *testing.T) {
 	file := getFile()
+	id, _ := GetResourceID(file)
 	testCase := []struct {
 		any
 		string
@@ -122,6 +168,18 @@ func TestConvertToString(t *testing.T) {
 			float32(5.0546941757202),
 			"5.0546941757202",
 		},
+		{
+			1e25,
+			"1.0E+25",
+		},
+		{
+			-0.00001,
+			"-1.0E-5",
+		},
+		{
+			0.0001,
+			"0.0001",
+		},
 		{
 			true,
 			"1",
@@ -140,12 +198,28 @@ func TestConvertToString(t *testing.T) {
 		},
 		{
 			file,
-			fmt.Sprintf("Resource id %p", file),
+			fmt.Sprintf("Resource id #%d", id),
 		},
 		{
 			Cat{name: "nabi", age: 3},
 			"name is nabi and 3 years old",
 		},
+		{
+			&Cat{name: "nabi", age: 3},
+			"name is nabi and 3 years old",
+		},
+		{
+			Bird{name: "tweety"},
+			"bird named tweety",
+		},
+		{
+			&Bird{name: "tweety"},
+			"bird named tweety",
+		},
+		{
+			FromStringer(&Color{name: "blue"}),
+			"color blue",
+		},
 	}
 
 	for _, tc := range testCase {
@@ -163,18 +237,717 @@ func TestConvertToString(t *testing.T) {
 	}
 
 	// Failing cases
-	errorCase := []any{
-		Dog{name: "choco", age: 5},
+	errorCase := []struct {
+		value any
+		err   string
+	}{
+		{Dog{name: "choco", age: 5}, "Object of class Dog could not be converted to string"},
+		{&Dog{name: "choco", age: 5}, "Object of class Dog could not be converted to string"},
+		{struct{}{}, "Object of class struct {} could not be converted to string"},
+		{(*Bird)(nil), "Object of class Bird could not be converted to string"},
+		{(*Cat)(nil), "Object of class Cat could not be converted to string"},
+		{func() {}, "unsupported type : func()"},
 	}
 
 	for _, tc := range errorCase {
-		testName := fmt.Sprintf("%v", tc)
+		testName := fmt.Sprintf("%T", tc.value)
 		t.Run(testName, func(t *testing.T) {
-			result, err := ConvertToString(tc)
+			result, err := ConvertToString(tc.value)
 			if err == nil {
 				t.Errorf("%s:  error, bug got %v", testName, result)
+			} else if err.Error() != tc.err {
+				t.Errorf("%s: expected error %q, but got %q", testName, tc.err, err)
 			}
 		})
 	}
 	file.Close()
 }
+
+func ExampleConvertToLong() {
+	// Leading numeric string
+	fmt.Println(ConvertToLong("12abc"))
+	// Leading whitespaces are allowed, but trailing ones are ignored
+	fmt.Println(ConvertToLong(" \t\n42 "))
+	// Exponent is not respected
+	fmt.Println(ConvertToLong("1e3"))
+	// Hexadecimal string
+	fmt.Println(ConvertToLong("0x1A"))
+	// Non-numeric string
+	fmt.Println(ConvertToLong("abc"))
+	// Float is truncated toward zero
+	fmt.Println(ConvertToLong(-3.99))
+	// Float out of range wraps around
+	fmt.Println(ConvertToLong(1e19))
+	// Bool
+	fmt.Println(ConvertToLong(true))
+	// Array
+	fmt.Println(ConvertToLong([]int{1, 2, 3}))
+	// Empty array
+	fmt.Println(ConvertToLong([]int{}))
+	// Object
+	fmt.Println(ConvertToLong(Dog{name: "choco", age: 5}))
+
+	// Output:
+	// 12 <nil>
+	// 42 <nil>
+	// 1 <nil>
+	// 0 <nil>
+	// 0 <nil>
+	// -3 <nil>
+	// -8446744073709551616 <nil>
+	// 1 <nil>
+	// 1 <nil>
+	// 0 <nil>
+	// 1 <nil>
+}
+
+// Test cases for ConvertToLong. These tests were created using the following
+// test cases in PHP as inspiration.
+//
+// Reference:
+//   - https://github.com/php/php-src/blob/php-5.6.40/ext/standard/tests/general_functions/intval.phpt
+//   - https://github.com/php/php-src/blob/php-5.6.40/ext/standard/tests/general_functions/intval_variation1.phpt
+//   - https://github.com/php/php-src/blob/php-5.6.40/Zend/tests/int_overflow_64bit.phpt
+//   - https://github.com/php/php-src/blob/php-5.6.40/Zend/tests/int_underflow_64bit.phpt
+func TestConvertToLong(t *testing.T) {
+	om := orderedmap.NewOrderedMap[any, any]()
+	om.Set("key", "value")
+
+	testCases := []struct {
+		value    any
+		expected int64
+	}{
+		{nil, 0},
+		{true, 1},
+		{false, 0},
+		{0, 0},
+		{int8(-128), -128},
+		{int16(32767), 32767},
+		{int32(-2147483648), -2147483648},
+		{int64(math.MaxInt64), math.MaxInt64},
+		{1.5, 1},
+		{-1.5, -1},
+		{float32(2.5), 2},
+		{0.5e-10, 0},
+		{1e19, -8446744073709551616},
+		{-1e19, 8446744073709551616},
+		{9.2233720368547758e18, math.MinInt64},
+		{-9.2233720368547758e18, math.MinInt64},
+		{2e64, 0},
+		{math.NaN(), math.MinInt64},
+		{math.Inf(1), math.MinInt64},
+		{math.Inf(-1), math.MinInt64},
+		{"", 0},
+		{" ", 0},
+		{"0", 0},
+		{"-0", 0},
+		{"12", 12},
+		{"-12", -12},
+		{"+12", 12},
+		{"012", 12},
+		{"0x1A", 0},
+		{"1e3", 1},
+		{"1.9", 1},
+		{"12abc", 12},
+		{"abc12", 0},
+		{" \t\n\r\v\f12", 12},
+		{"12 ", 12},
+		{"- 12", 0},
+		{"--12", 0},
+		{"1\x002", 1},
+		{"9223372036854775807", math.MaxInt64},
+		{"9223372036854775808", math.MaxInt64},
+		{"99999999999999999999", math.MaxInt64},
+		{"-9223372036854775808", math.MinInt64},
+		{"-9223372036854775809", math.MinInt64},
+		{"-99999999999999999999", math.MinInt64},
+		{[]int{}, 0},
+		{[]int{0}, 1},
+		{[2]string{}, 1},
+		{map[string]int{}, 0},
+		{map[string]int{"a": 0}, 1},
+		{*orderedmap.NewOrderedMap[any, any](), 0},
+		{om, 1},
+		{Cat{name: "nabi", age: 3}, 1},
+		{&Dog{name: "choco", age: 5}, 1},
+	}
+
+	for _, tc := range testCases {
+		testName := fmt.Sprintf("%#v", tc.value)
+		t.Run(testName, func(t *testing.T) {
+			result, err := ConvertToLong(tc.value)
+			if err != nil {
+				t.Errorf("%s: expected success to convert, but got error %v", testName, err)
+			}
+			if result != tc.expected {
+				t.Errorf("%s: expected %d, but got %d", testName, tc.expected, result)
+			}
+		})
+	}
+
+	file := getFile()
+	defer file.Close()
+	result, err := ConvertToLong(file)
+	if id, _ := GetResourceID(file); err != nil || result != int64(id) {
+		t.Errorf("expected resource ID of file, but got (%v, %v)", result, err)
+	}
+
+	_, err = ConvertToLong(func() {})
+	if err == nil || err.Error() != "unsupported type : func()" {
+		t.Errorf("expected unsupported type error, but got %v", err)
+	}
+}
+
+// TestConvertToLongVersions tests behaviors of ConvertToLong which differ
+// between PHP versions.
+//
+// References:
+//   - https://wiki.php.net/rfc/integer_semantics
+//   - https://wiki.php.net/rfc/invalid_strings_in_arithmetic
+func TestConvertToLongVersions(t *testing.T) {
+	testCases := []struct {
+		value any
+		php56 int64
+		php70 int64
+		php71 int64
+	}{
+		{math.NaN(), math.MinInt64, 0, 0},
+		{math.Inf(1), math.MinInt64, 0, 0},
+		{math.Inf(-1), math.MinInt64, 0, 0},
+		{1e19, -8446744073709551616, -8446744073709551616, -8446744073709551616},
+		{"1e3", 1, 1, 1000},
+		{" 1.9e1abc", 1, 1, 19},
+		{"1e20", 1, 1, math.MaxInt64},
+		{"-1e20", -1, -1, math.MinInt64},
+		{"1e1000", 1, 1, 0},
+		{"12abc", 12, 12, 12},
+		{"0x1A", 0, 0, 0},
+		{"99999999999999999999", math.MaxInt64, math.MaxInt64, math.MaxInt64},
+	}
+
+	for _, tc := range testCases {
+		for _, v := range []struct {
+			version  Version
+			expected int64
+		}{{PHP56, tc.php56}, {PHP70, tc.php70}, {PHP71, tc.php71}, {PHP83, tc.php71}} {
+			result, err := NewRuntime(v.version).ConvertToLong(tc.value)
+			if err != nil || result != v.expected {
+				t.Errorf("ConvertToLong(%#v) on %d = (%d, %v); want %d", tc.value, v.version, result, err, v.expected)
+			}
+		}
+	}
+}
+
+func ExampleConvertToDouble() {
+	// Numeric string
+	fmt.Println(ConvertToDouble("-1.3e3"))
+	// Leading numeric string
+	fmt.Println(ConvertToDouble("10.2 Some Dollars"))
+	// Leading whitespaces and trailing garbage
+	fmt.Println(ConvertToDouble(" 1.5e3xyz"))
+	// Non-numeric string
+	fmt.Println(ConvertToDouble("bob-1.3e3"))
+	// Strings accepted by strconv.ParseFloat but not by PHP
+	fmt.Println(ConvertToDouble("inf"))
+	fmt.Println(ConvertToDouble("0x1p-2"))
+	// Int
+	fmt.Println(ConvertToDouble(-2147483648))
+	// Bool
+	fmt.Println(ConvertToDouble(true))
+	// Array
+	fmt.Println(ConvertToDouble([]int{1, 2, 3}))
+
+	// Output:
+	// -1300 <nil>
+	// 10.2 <nil>
+	// 1500 <nil>
+	// 0 <nil>
+	// 0 <nil>
+	// 0 <nil>
+	// -2.147483648e+09 <nil>
+	// 1 <nil>
+	// 1 <nil>
+}
+
+// Test cases for ConvertToDouble. These tests were created using the following
+// test cases in PHP as inspiration.
+//
+// Reference:
+//   - https://github.com/php/php-src/blob/php-5.6.40/ext/standard/tests/general_functions/floatval.phpt
+//   - https://github.com/php/php-src/blob/php-5.6.40/ext/standard/tests/general_functions/floatval_variation1.phpt
+//   - https://github.com/php/php-src/blob/php-5.6.40/ext/standard/tests/general_functions/settype_variation4.phpt
+func TestConvertToDouble(t *testing.T) {
+	testCases := []struct {
+		value    any
+		expected float64
+	}{
+		// floatval.phpt
+		{0.0, 0},
+		{1.0, 1},
+		{-1.0, -1},
+		{1.234, 1.234},
+		{-1.234, -1.234},
+		{1.2e3, 1200},
+		{-1.2e3, -1200},
+		{10.0000000000000000005, 10},
+		{10.5e+5, 1050000},
+		{1e5, 100000},
+		{-1e5, -100000},
+		{1e-5, 1e-5},
+		{-1e-1, -0.1},
+		{.5e+7, 5000000},
+		{-.5e+7, -5000000},
+		// floatval_variation1.phpt
+		{"-2147483648", -2147483648},
+		{"2147483647", 2147483647},
+		{"0.0", 0},
+		{"1.0", 1},
+		{"-1.3e3", -1300},
+		{"bob-1.3e3", 0},
+		{"10 Some dollars", 10},
+		{"10.2 Some Dollars", 10.2},
+		{"", 0},
+		{true, 1},
+		{nil, 0},
+		// settype_variation4.phpt
+		{"1e5", 100000},
+		{"-1e5", -100000},
+		{"1e-5", 1e-5},
+		{"-1e-1", -0.1},
+		{"1e+5", 100000},
+		{"-1e+5", -100000},
+		{"1E5", 100000},
+		{".5e+7", 5000000},
+		{"-.5e+7", -5000000},
+		{"0x1", 0},
+		{"0xff", 0},
+		{"0123", 123},
+		{"-0123", -123},
+		{"10.0000000000000000005", 10},
+		{"\t\n 1.5", 1.5},
+		{"1.5\t\n ", 1.5},
+		{"inf", 0},
+		{"NAN", 0},
+		{"1_000.5", 1},
+		{"1e1000", math.Inf(1)},
+		{false, 0},
+		{int64(math.MaxInt64), 9223372036854775807},
+		{float32(0.5), 0.5},
+		{[]int{}, 0},
+		{[]int{0}, 1},
+		{map[string]int{"a": 0}, 1},
+		{Cat{name: "nabi", age: 3}, 1},
+		{Dog{name: "choco", age: 5}, 1},
+	}
+
+	for _, tc := range testCases {
+		testName := fmt.Sprintf("%#v", tc.value)
+		t.Run(testName, func(t *testing.T) {
+			result, err := ConvertToDouble(tc.value)
+			if err != nil {
+				t.Errorf("%s: expected success to convert, but got error %v", testName, err)
+			}
+			if result != tc.expected {
+				t.Errorf("%s: expected %v, but got %v", testName, tc.expected, result)
+			}
+		})
+	}
+
+	result, err := ConvertToDouble(math.NaN())
+	if err != nil || !math.IsNaN(result) {
+		t.Errorf("expected NaN, but got (%v, %v)", result, err)
+	}
+
+	_, err = ConvertToDouble(func() {})
+	if err == nil || err.Error() != "unsupported type : func()" {
+		t.Errorf("expected unsupported type error, but got %v", err)
+	}
+}
+
+// XMLElement mimics PHP's SimpleXMLElement, which is converted to false if it
+// is an empty element.
+type XMLElement struct {
+	children []XMLElement
+}
+
+func (e *XMLElement) ToBool() bool {
+	return len(e.children) > 0
+}
+
+func ExampleConvertToBool() {
+	// Strings
+	fmt.Println(ConvertToBool(""))
+	fmt.Println(ConvertToBool("0"))
+	fmt.Println(ConvertToBool("0.0"))
+	fmt.Println(ConvertToBool("false"))
+	// Numbers
+	fmt.Println(ConvertToBool(0))
+	fmt.Println(ConvertToBool(-0.0))
+	fmt.Println(ConvertToBool(math.NaN()))
+	// Arrays
+	fmt.Println(ConvertToBool([]int{}))
+	fmt.Println(ConvertToBool(map[string]int{"a": 0}))
+	// Objects
+	fmt.Println(ConvertToBool(Dog{name: "choco", age: 5}))
+	fmt.Println(ConvertToBool(XMLElement{}))
+
+	// Output:
+	// false
+	// false
+	// true
+	// true
+	// false
+	// false
+	// true
+	// false
+	// true
+	// true
+	// false
+}
+
+// Test cases for ConvertToBool. These tests were created using the following
+// test cases in PHP as inspiration.
+//
+// Reference:
+//   - https://github.com/php/php-src/blob/php-5.6.40/ext/standard/tests/general_functions/boolval.phpt
+//   - https://github.com/php/php-src/blob/php-5.6.40/ext/standard/tests/general_functions/boolval_variation1.phpt
+//   - https://github.com/php/php-src/blob/php-5.6.40/ext/simplexml/tests/bug38406.phpt
+func TestConvertToBool(t *testing.T) {
+	file := getFile()
+	defer file.Close()
+
+	testCases := []struct {
+		value    any
+		expected bool
+	}{
+		// boolval.phpt
+		{0, false},
+		{42, true},
+		{0.0, false},
+		{4.2, true},
+		{"", false},
+		{"string", true},
+		{"0", false},
+		{"1", true},
+		{[]int{1, 2}, true},
+		{[]int{}, false},
+		{Sample{}, true},
+		// boolval_variation1.phpt
+		{-2147483648, true},
+		{int64(math.MaxInt64), true},
+		{-0.5, true},
+		{1e-10, true},
+		{"0.0", true},
+		{"00", true},
+		{" ", true},
+		{" 0", true},
+		{"0 ", true},
+		{"\x00", true},
+		{true, true},
+		{false, false},
+		{nil, false},
+		{file, true},
+		// NaN is not equal to 0, so it is true
+		{math.NaN(), true},
+		{math.Inf(-1), true},
+		{math.Copysign(0, -1), false},
+		{float32(0), false},
+		{int8(0), false},
+		{[1]int{}, true},
+		{[0]int{}, false},
+		{map[string]int{}, false},
+		{*orderedmap.NewOrderedMap[any, any](), false},
+		{omap("key", "value"), true},
+		// Objects are always true, unless they implement BoolCastable
+		{Cat{name: "nabi", age: 3}, true},
+		{&Dog{}, true},
+		{struct{}{}, true},
+		{XMLElement{}, false},
+		{&XMLElement{}, false},
+		{XMLElement{children: []XMLElement{{}}}, true},
+		{func() {}, true},
+	}
+
+	for _, tc := range testCases {
+		testName := fmt.Sprintf("%#v", tc.value)
+		t.Run(testName, func(t *testing.T) {
+			if result := ConvertToBool(tc.value); result != tc.expected {
+				t.Errorf("%s: expected %v, but got %v", testName, tc.expected, result)
+			}
+		})
+	}
+}
+
+func ExampleIsNumeric() {
+	fmt.Println(IsNumeric("42"))
+	fmt.Println(IsNumeric(" 1337e0"))
+	fmt.Println(IsNumeric("0x539"))
+	fmt.Println(IsNumeric("not numeric"))
+	fmt.Println(IsNumeric(9.1))
+	fmt.Println(IsNumeric(nil))
+
+	// Trailing whitespaces are allowed since PHP 8, and hexadecimal strings
+	// are not allowed since PHP 7.
+	php8 := NewRuntime(PHP80)
+	fmt.Println(IsNumeric("42 "), php8.IsNumeric("42 "))
+	fmt.Println(IsNumeric("0x1A"), php8.IsNumeric("0x1A"))
+
+	// Output:
+	// true
+	// true
+	// true
+	// false
+	// true
+	// false
+	// false true
+	// true false
+}
+
+func ExampleIsNumericString() {
+	fmt.Printf("%+v\n", IsNumericString("123", DisallowErrors))
+	fmt.Printf("%+v\n", IsNumericString("1.5e3", DisallowErrors))
+	fmt.Printf("%+v\n", IsNumericString("123abc", DisallowErrors))
+	fmt.Printf("%+v\n", IsNumericString("123abc", AllowErrorsSilently))
+	fmt.Printf("%+v\n", IsNumericString("9223372036854775808", DisallowErrors))
+
+	// Output:
+	// {Type:1 Long:123 Double:0 Overflow:0 TrailingData:false}
+	// {Type:2 Long:0 Double:1500 Overflow:0 TrailingData:false}
+	// {Type:0 Long:0 Double:0 Overflow:0 TrailingData:false}
+	// {Type:1 Long:123 Double:0 Overflow:0 TrailingData:true}
+	// {Type:2 Long:0 Double:9.223372036854776e+18 Overflow:1 TrailingData:false}
+}
+
+func TestIsNumericString(t *testing.T) {
+	long := func(l int64) NumericString { return NumericString{Type: NumericLong, Long: l} }
+	double := func(d float64) NumericString { return NumericString{Type: NumericDouble, Double: d} }
+	trailing := func(n NumericString) NumericString { n.TrailingData = true; return n }
+	overflow := func(n NumericString, o int) NumericString { n.Overflow = o; return n }
+	none := NumericString{}
+
+	testCases := []struct {
+		input       string
+		allowErrors AllowErrors
+		php56       NumericString
+		php74       NumericString
+		php80       NumericString
+	}{
+		{"", DisallowErrors, none, none, none},
+		{" ", DisallowErrors, none, none, none},
+		{"0", DisallowErrors, long(0), long(0), long(0)},
+		{"123", DisallowErrors, long(123), long(123), long(123)},
+		{"-123", DisallowErrors, long(-123), long(-123), long(-123)},
+		{"+123", DisallowErrors, long(123), long(123), long(123)},
+		{"0123", DisallowErrors, long(123), long(123), long(123)},
+		{"000000000000000000000001", DisallowErrors, long(1), long(1), long(1)},
+		{" \t\n\r\v\f123", DisallowErrors, long(123), long(123), long(123)},
+		{"123 ", DisallowErrors, none, none, long(123)},
+		{"123 ", AllowErrorsSilently, trailing(long(123)), trailing(long(123)), long(123)},
+		{"123\x00", DisallowErrors, none, none, none},
+		{"123abc", DisallowErrors, none, none, none},
+		{"123abc", AllowErrorsSilently, trailing(long(123)), trailing(long(123)), trailing(long(123))},
+		{"123abc", AllowErrorsWithNotice, trailing(long(123)), trailing(long(123)), trailing(long(123))},
+		{"abc", AllowErrorsSilently, none, none, none},
+		{"- 1", AllowErrorsSilently, none, none, none},
+		{"--1", AllowErrorsSilently, none, none, none},
+		{"1.5", DisallowErrors, double(1.5), double(1.5), double(1.5)},
+		{"-1.5", DisallowErrors, double(-1.5), double(-1.5), double(-1.5)},
+		{".5", DisallowErrors, double(.5), double(.5), double(.5)},
+		{"5.", DisallowErrors, double(5), double(5), double(5)},
+		{".", DisallowErrors, none, none, none},
+		{".e1", DisallowErrors, none, none, none},
+		{"1e3", DisallowErrors, double(1000), double(1000), double(1000)},
+		{"1E-3", DisallowErrors, double(.001), double(.001), double(.001)},
+		{"1.5e3", DisallowErrors, double(1500), double(1500), double(1500)},
+		{"1e", DisallowErrors, none, none, none},
+		{"1e", AllowErrorsSilently, trailing(long(1)), trailing(long(1)), trailing(long(1))},
+		{"1e+", AllowErrorsSilently, trailing(long(1)), trailing(long(1)), trailing(long(1))},
+		{"1.5.5", AllowErrorsSilently, trailing(double(1.5)), trailing(double(1.5)), trailing(double(1.5))},
+		{"1e5.5", AllowErrorsSilently, trailing(double(1e5)), trailing(double(1e5)), trailing(double(1e5))},
+		{"inf", AllowErrorsSilently, none, none, none},
+		{"0x1A", DisallowErrors, long(26), none, none},
+		{"0x1A", AllowErrorsSilently, long(26), trailing(long(0)), trailing(long(0))},
+		{" 0X1a", DisallowErrors, long(26), none, none},
+		{"-0x1A", DisallowErrors, none, none, none},
+		{"0x", AllowErrorsSilently, trailing(long(0)), trailing(long(0)), trailing(long(0))},
+		{"0x7FFFFFFFFFFFFFFF", DisallowErrors, long(math.MaxInt64), none, none},
+		{"0x8000000000000000", DisallowErrors, overflow(double(9223372036854775808), 1), none, none},
+		{"9223372036854775807", DisallowErrors, long(math.MaxInt64), long(math.MaxInt64), long(math.MaxInt64)},
+		{"9223372036854775808", DisallowErrors, overflow(double(9223372036854775808), 1), overflow(double(9223372036854775808), 1), overflow(double(9223372036854775808), 1)},
+		{"-9223372036854775808", DisallowErrors, long(math.MinInt64), long(math.MinInt64), long(math.MinInt64)},
+		{"-9223372036854775809", DisallowErrors, overflow(double(-9223372036854775808), -1), overflow(double(-9223372036854775808), -1), overflow(double(-9223372036854775808), -1)},
+		{"99999999999999999999", DisallowErrors, overflow(double(1e20), 1), overflow(double(1e20), 1), overflow(double(1e20), 1)},
+		{"-99999999999999999999abc", AllowErrorsSilently, trailing(overflow(double(-1e20), -1)), trailing(overflow(double(-1e20), -1)), trailing(overflow(double(-1e20), -1))},
+	}
+
+	for _, tc := range testCases {
+		for _, v := range []struct {
+			version  Version
+			expected NumericString
+		}{
+			{PHP56, tc.php56},
+			{PHP74, tc.php74},
+			{PHP80, tc.php80},
+		} {
+			testName := fmt.Sprintf("%q/%d/%d", tc.input, tc.allowErrors, v.version)
+			t.Run(testName, func(t *testing.T) {
+				result := NewRuntime(v.version).IsNumericString(tc.input, tc.allowErrors)
+				if result != v.expected {
+					t.Errorf("expected %+v, but got %+v", v.expected, result)
+				}
+			})
+		}
+	}
+}
+
+// Test cases for IsNumeric. These tests were created using the following test
+// cases in PHP as inspiration.
+//
+// Reference:
+//   - https://github.com/php/php-src/blob/php-5.6.40/ext/standard/tests/general_functions/is_numeric.phpt
+func TestIsNumeric(t *testing.T) {
+	numerics := []any{
+		0, 1, -1, 0.0, 1.0, -1.0, .5, -.5, -.5e-2, .5e+2, 0.70000000, 1234567890123456, -1234567890123456,
+		984847472827282718178.0, 123.56e30, 12.34e-30, 1e5, -1e-1, 0x7fffffff, int64(math.MinInt64), 0123,
+		int8(1), int16(1), int32(1), float32(1), math.NaN(), math.Inf(1),
+		"-1", "1e2", " 1", "2974394749328742328432", "-1e-2", "1", "0123", "-0123", "+0123", "-.5e-2", ".5",
+		"1.", "0x1A", "0X1A", " 0xff", "0x7fffffffffffffff", "0xffffffffffffffffff",
+	}
+	nonNumerics := []any{
+		"-0x80001", "+0x80001", "-0x80001.5", "0x80001.5", "@$%#$%^$%^&^", []any{}, []int{1, 2, 4},
+		[]string{"string", "test"}, "", " ", ".", "-", "string", "1e", "-1e", "1e2\x00", "1 ", "1\t", "1\n",
+		"\x001", "1,5", "1_000", "inf", "nan", "0x", nil, true, false, Cat{}, getFile(),
+	}
+	for _, value := range numerics {
+		testName := fmt.Sprintf("%#v", value)
+		t.Run(testName, func(t *testing.T) {
+			if !IsNumeric(value) {
+				t.Errorf("%s: expected numeric, but got non-numeric", testName)
+			}
+		})
+	}
+	for _, value := range nonNumerics {
+		testName := fmt.Sprintf("%#v", value)
+		t.Run(testName, func(t *testing.T) {
+			if IsNumeric(value) {
+				t.Errorf("%s: expected non-numeric, but got numeric", testName)
+			}
+		})
+	}
+}
+
+func ExampleIncrementValue() {
+	fmt.Println(IncrementValue("a"))
+	fmt.Println(IncrementValue("Az"))
+	fmt.Println(IncrementValue("zz"))
+	fmt.Println(IncrementValue("A99"))
+	fmt.Println(IncrementValue("9"))
+	fmt.Println(IncrementValue(nil))
+	// Output:
+	// b
+	// Ba
+	// aaa
+	// B00
+	// 10
+	// 1
+}
+
+func ExampleDecrementValue() {
+	fmt.Println(DecrementValue("10"))
+	fmt.Println(DecrementValue("b"))
+	fmt.Println(DecrementValue(""))
+	fmt.Println(DecrementValue(nil))
+	// Output:
+	// 9
+	// b
+	// -1
+	// <nil>
+}
+
+func TestIncrementValue(t *testing.T) {
+	testCases := []struct {
+		input any
+		php56 any
+		php74 any
+		php80 any
+	}{
+		{0, int64(1), int64(1), int64(1)},
+		{int8(-1), int64(0), int64(0), int64(0)},
+		{math.MaxInt64, float64(math.MaxInt64) + 1, float64(math.MaxInt64) + 1, float64(math.MaxInt64) + 1},
+		{1.5, 2.5, 2.5, 2.5},
+		{float32(-0.5), 0.5, 0.5, 0.5},
+		{nil, int64(1), int64(1), int64(1)},
+		{true, true, true, true},
+		{false, false, false, false},
+		{"", "1", "1", "1"},
+		{"0", int64(1), int64(1), int64(1)},
+		{"-1", int64(0), int64(0), int64(0)},
+		{"1.5", 2.5, 2.5, 2.5},
+		{"1e2", 101.0, 101.0, 101.0},
+		{" 1", int64(2), int64(2), int64(2)},
+		{"1 ", "1 ", "1 ", int64(2)},
+		{"9223372036854775807", float64(math.MaxInt64) + 1, float64(math.MaxInt64) + 1, float64(math.MaxInt64) + 1},
+		{"0x1A", int64(27), "0x1B", "0x1B"},
+		{"a", "b", "b", "b"},
+		{"z", "aa", "aa", "aa"},
+		{"Z", "AA", "AA", "AA"},
+		{"Az", "Ba", "Ba", "Ba"},
+		{"zZ9", "aaA0", "aaA0", "aaA0"},
+		{"a9", "b0", "b0", "b0"},
+		{"9z", "10a", "10a", "10a"},
+		{"1a", "1b", "1b", "1b"},
+		{"a-z", "a-a", "a-a", "a-a"},
+		{"-z", "-a", "-a", "-a"},
+		{"a ", "a ", "a ", "a "},
+		{"é", "é", "é", "é"},
+	}
+
+	php56, php74, php80 := NewRuntime(PHP56), NewRuntime(PHP74), NewRuntime(PHP80)
+	for _, tc := range testCases {
+		for _, c := range []struct {
+			runtime  *Runtime
+			expected any
+		}{{php56, tc.php56}, {php74, tc.php74}, {php80, tc.php80}} {
+			if result := c.runtime.IncrementValue(tc.input); result != c.expected {
+				t.Errorf("IncrementValue(%#v) on PHP %d = %#v; want %#v", tc.input, c.runtime.Version, result, c.expected)
+			}
+		}
+	}
+
+	arr := []int{1}
+	if result := IncrementValue(arr); !Identical(result, arr) {
+		t.Errorf("IncrementValue(%#v) = %#v; want unchanged", arr, result)
+	}
+}
+
+func TestDecrementValue(t *testing.T) {
+	testCases := []struct {
+		input any
+		php56 any
+		php80 any
+	}{
+		{0, int64(-1), int64(-1)},
+		{math.MinInt64, float64(math.MinInt64) - 1, float64(math.MinInt64) - 1},
+		{1.5, 0.5, 0.5},
+		{nil, nil, nil},
+		{true, true, true},
+		{"", int64(-1), int64(-1)},
+		{"0", int64(-1), int64(-1)},
+		{"1.5", 0.5, 0.5},
+		{"-9223372036854775808", float64(math.MinInt64) - 1, float64(math.MinInt64) - 1},
+		{"1 ", "1 ", int64(0)},
+		{"0x1A", int64(25), "0x1A"},
+		{"b", "b", "b"},
+		{"aa", "aa", "aa"},
+	}
+
+	php56, php80 := NewRuntime(PHP56), NewRuntime(PHP80)
+	for _, tc := range testCases {
+		if result := php56.DecrementValue(tc.input); result != tc.php56 {
+			t.Errorf("DecrementValue(%#v) on PHP 5.6 = %#v; want %#v", tc.input, result, tc.php56)
+		}
+		if result := php80.DecrementValue(tc.input); result != tc.php80 {
+			t.Errorf("DecrementValue(%#v) on PHP 8.0 = %#v; want %#v", tc.input, result, tc.php80)
+		}
+	}
+}
@@ -0,0 +1,255 @@
+package gophplib
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// zendStrtod is a ported function that works exactly the same as PHP's
+// zend_strtod function. It parses the longest prefix of s which forms a decimal
+// floating point number, and returns the parsed value and the length of the
+// parsed prefix. If s does not start with a number, it returns 0 and 0.
+//
+// Unlike strconv.ParseFloat, it skips leading whitespaces and ignores trailing
+// garbage. (ex: " 1.5e3xyz" is parsed as 1500) On the other hand, it does not
+// accept "inf", "nan", hexadecimal floats and underscores, which are accepted
+// by strconv.ParseFloat. The exponent part is consumed only if at least one
+// digit follows the 'e' or 'E' character and an optional sign.
+//
+// Numbers too large to be represented as float64 are parsed as infinity, and
+// numbers too small are parsed as zero or a subnormal number.
+//
+// References:
+//   - https://github.com/php/php-src/blob/php-5.6.40/Zend/zend_strtod.c
+//   - https://github.com/php/php-src/blob/php-8.3.0/Zend/zend_strtod.c
+func zendStrtod(s string) (float64, int) {
+	i := 0
+	for i < len(s) && isAsciiWhitespace(s[i]) {
+		i++
+	}
+	begin := i
+	if i < len(s) && (s[i] == '+' || s[i] == '-') {
+		i++
+	}
+
+	// Mantissa
+	digits := 0
+	for ; i < len(s) && isdigit(s[i]); i++ {
+		digits++
+	}
+	if i < len(s) && s[i] == '.' {
+		i++
+		for ; i < len(s) && isdigit(s[i]); i++ {
+			digits++
+		}
+	}
+	if digits == 0 {
+		return 0, 0
+	}
+
+	// Exponent
+	if i < len(s) && (s[i] == 'e' || s[i] == 'E') {
+		j := i + 1
+		if j < len(s) && (s[j] == '+' || s[j] == '-') {
+			j++
+		}
+		if j < len(s) && isdigit(s[j]) {
+			for j < len(s) && isdigit(s[j]) {
+				j++
+			}
+			i = j
+		}
+	}
+
+	// The prefix is guaranteed to be a valid decimal number here, so the only
+	// possible error is strconv.ErrRange, for which strconv.ParseFloat returns
+	// ±Inf or ±0 just like zend_strtod.
+	f, _ := strconv.ParseFloat(s[begin:i], 64)
+	return f, i
+}
+
+// isdigit is a ported function that works exactly the same as C's isdigit
+// function.
+//
+// References:
+//   - https://en.cppreference.com/w/c/string/byte/isdigit
+func isdigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+// zendHexStrtod is a ported function that works exactly the same as PHP's
+// zend_hex_strtod function. It parses the longest prefix of s which forms a
+// hexadecimal number with an optional "0x" or "0X" prefix, and returns the parsed
+// value and the length of the parsed prefix. If s does not start with a
+// hexadecimal number, it returns 0 and 0.
+//
+// References:
+//   - https://github.com/php/php-src/blob/php-5.6.40/Zend/zend_strtod.c
+func zendHexStrtod(s string) (float64, int) {
+	i := 0
+	if len(s) >= 2 && s[0] == '0' && (s[1] == 'x' || s[1] == 'X') {
+		i += 2
+	}
+
+	found := false
+	value := 0.0
+	for ; i < len(s) && isxdigit(s[i]); i++ {
+		found = true
+		value = value*16 + float64(htoi('0', s[i]))
+	}
+
+	if !found {
+		return 0, 0
+	}
+	return value, i
+}
+
+// zendDtoa is a simplified port of PHP's zend_dtoa function, supporting mode
+// 0 and 2 only. It returns the significant digits of the given float without
+// trailing zeros, the position of the decimal point relative to the digits,
+// and whether the float is negative.
+//
+// Mode 0 returns the shortest digits which round-trip, and mode 2 returns at
+// most ndigits digits, rounded correctly. Like zend_dtoa, decpt is 9999 for
+// NaN and infinities, and digits is either "NaN" or "Infinity" for them.
+//
+// Reference:
+//   - https://github.com/php/php-src/blob/php-8.3.0/Zend/zend_strtod.c
+func zendDtoa(value float64, mode, ndigits int) (digits string, decpt int, negative bool) {
+	negative = math.Signbit(value)
+	if math.IsNaN(value) {
+		return "NaN", 9999, false
+	}
+	if math.IsInf(value, 0) {
+		return "Infinity", 9999, negative
+	}
+	if value == 0 {
+		return "0", 1, negative
+	}
+
+	prec := -1
+	if mode != 0 {
+		if ndigits <= 0 {
+			ndigits = 1
+		}
+		prec = ndigits - 1
+	}
+
+	// Format the float as "d.ddde±xx" and split the digits and the exponent
+	formatted := strconv.FormatFloat(math.Abs(value), 'e', prec, 64)
+	mantissa, exponent, _ := strings.Cut(formatted, "e")
+	exp, _ := strconv.Atoi(exponent)
+	digits = strings.TrimRight(strings.Replace(mantissa, ".", "", 1), "0")
+	return digits, exp + 1, negative
+}
+
+// zendGcvt is a ported function that works exactly the same as PHP's zend_gcvt
+// function, which was php_gcvt before PHP 8.1. It formats the given float
+// like C's "%G" format with ndigit significant digits, but the exponent is
+// not padded and at least one digit follows the decimal point in exponential
+// format. (ex: "1.0E+25" instead of "1E+25")
+//
+// If ndigit is negative, it uses the shortest digits which round-trip, and
+// chooses the format as if ndigit is 17.
+//
+// Reference:
+//   - https://github.com/php/php-src/blob/php-5.6.40/main/snprintf.c
+//   - https://github.com/php/php-src/blob/php-7.1.0/main/snprintf.c
+//   - https://github.com/php/php-src/blob/php-8.3.0/Zend/zend_strtod.c
+func zendGcvt(value float64, ndigit int, decPoint, expChar byte) string {
+	mode := 2
+	if ndigit < 0 {
+		mode = 0
+		ndigit = 17
+	}
+	digits, decpt, negative := zendDtoa(value, mode, ndigit)
+
+	var buf strings.Builder
+	if decpt == 9999 {
+		// Infinity or Nan, convert to inf or nan with sign. We assume the
+		// buffer is at least ndigit long.
+		str := "NAN"
+		if digits[0] == 'I' {
+			str = "INF"
+			if negative {
+				str = "-INF"
+			}
+		}
+		if len(str) > ndigit {
+			str = str[:ndigit]
+		}
+		return str
+	}
+
+	if negative {
+		buf.WriteByte('-')
+	}
+
+	if (decpt >= 0 && decpt > ndigit) || decpt < -3 {
+		// exponential format (e.g. 1.0e+00)
+		decpt--
+		buf.WriteByte(digits[0])
+		buf.WriteByte(decPoint)
+		if len(digits) == 1 {
+			buf.WriteByte('0')
+		} else {
+			buf.WriteString(digits[1:])
+		}
+		buf.WriteByte(expChar)
+		if decpt < 0 {
+			buf.WriteByte('-')
+			decpt = -decpt
+		} else {
+			buf.WriteByte('+')
+		}
+		buf.WriteString(strconv.Itoa(decpt))
+	} else if decpt < 0 {
+		// standard format 0.
+		buf.WriteByte('0')
+		buf.WriteByte(decPoint)
+		buf.WriteString(strings.Repeat("0", -decpt))
+		buf.WriteString(digits)
+	} else {
+		// standard format
+		if decpt <= len(digits) {
+			buf.WriteString(digits[:decpt])
+		} else {
+			buf.WriteString(digits)
+			buf.WriteString(strings.Repeat("0", decpt-len(digits)))
+		}
+		if decpt < len(digits) {
+			if decpt == 0 {
+				// zero before decimal point
+				buf.WriteByte('0')
+			}
+			buf.WriteByte(decPoint)
+			buf.WriteString(digits[decpt:])
+		}
+	}
+	return buf.String()
+}
+
+// smartStrAppendDouble is a ported function that works exactly the same as
+// PHP's smart_str_append_double function. It appends the given float
+// formatted with the given precision to dst, and appends ".0" if zeroFraction
+// is true and the formatted float looks like an integer.
+//
+// Precision of 0 is treated as 1, and negative precision means the shortest
+// digits which round-trip, like the "precision" and "serialize_precision" INI
+// settings.
+//
+// Reference:
+//   - https://github.com/php/php-src/blob/php-8.3.0/Zend/zend_smart_str.c
+func smartStrAppendDouble(dst []byte, num float64, precision int, zeroFraction bool) []byte {
+	// Model snprintf precision behavior.
+	if precision == 0 {
+		precision = 1
+	}
+	str := zendGcvt(num, precision, '.', 'E')
+	dst = append(dst, str...)
+	if zeroFraction && !math.IsNaN(num) && !math.IsInf(num, 0) && !strings.ContainsAny(str, ".eE") {
+		dst = append(dst, ".0"...)
+	}
+	return dst
+}
@@ -0,0 +1,151 @@
+package gophplib
+
+import (
+	"math"
+	"testing"
+)
+
+func TestZendStrtod(t *testing.T) {
+	cases := []struct {
+		input  string
+		value  float64
+		length int
+	}{
+		{"", 0, 0},
+		{"abc", 0, 0},
+		{"0", 0, 1},
+		{"1.5", 1.5, 3},
+		{"-1.5", -1.5, 4},
+		{"+1.5", 1.5, 4},
+		{".5", 0.5, 2},
+		{"5.", 5, 2},
+		{".", 0, 0},
+		{"-", 0, 0},
+		{"-.", 0, 0},
+		{"+-1", 0, 0},
+		{"1e3", 1000, 3},
+		{"1E3", 1000, 3},
+		{"1e+3", 1000, 4},
+		{"1e-3", 0.001, 4},
+		{"1e", 1, 1},
+		{"1e+", 1, 1},
+		{"1ex", 1, 1},
+		{"1.5e3xyz", 1500, 5},
+		{" \t\n\r\v\f1.5", 1.5, 9},
+		{"1.5 ", 1.5, 3},
+		{"- 1", 0, 0},
+		{"0x1A", 0, 1},
+		{"0x1p-2", 0, 1},
+		{"inf", 0, 0},
+		{"INF", 0, 0},
+		{"nan", 0, 0},
+		{"infinity", 0, 0},
+		{"1_000", 1, 1},
+		{"1,5", 1, 1},
+		{"00012.50", 12.5, 8},
+		{"1e400", math.Inf(1), 5},
+		{"-1e400", math.Inf(-1), 6},
+		{"1e-400", 0, 6},
+		{"4.9e-324", 5e-324, 8},
+		{"9007199254740993", 9007199254740992, 16},
+		{"10.0000000000000000005", 10, 22},
+	}
+
+	for _, c := range cases {
+		t.Run(c.input, func(t *testing.T) {
+			value, length := zendStrtod(c.input)
+			if value != c.value || length != c.length {
+				t.Errorf("expected (%v, %d), got (%v, %d)", c.value, c.length, value, length)
+			}
+		})
+	}
+}
+
+func TestZendStrtodNegativeZero(t *testing.T) {
+	value, length := zendStrtod("-0.0")
+	if value != 0 || !math.Signbit(value) || length != 4 {
+		t.Errorf("expected (-0, 4), got (%v, %d)", value, length)
+	}
+}
+
+func TestZendHexStrtod(t *testing.T) {
+	cases := []struct {
+		input  string
+		value  float64
+		length int
+	}{
+		{"", 0, 0},
+		{"0x", 0, 0},
+		{"xyz", 0, 0},
+		{"0x1A", 26, 4},
+		{"0X1a", 26, 4},
+		{"1A", 26, 2},
+		{"0x1Ag", 26, 4},
+		{"0xFFFFFFFFFFFFFFFF", 18446744073709551615, 18},
+	}
+
+	for _, c := range cases {
+		t.Run(c.input, func(t *testing.T) {
+			value, length := zendHexStrtod(c.input)
+			if value != c.value || length != c.length {
+				t.Errorf("expected (%v, %d), got (%v, %d)", c.value, c.length, value, length)
+			}
+		})
+	}
+}
+
+// TestSmartStrAppendDouble tests formatting floats with various precisions.
+//
+// References:
+//   - https://github.com/php/php-src/blob/php-7.1.0/tests/basic/precision.phpt
+func TestSmartStrAppendDouble(t *testing.T) {
+	cases := []struct {
+		value        float64
+		precision    int
+		zeroFraction bool
+		expected     string
+	}{
+		{0, 14, false, "0"},
+		{math.Copysign(0, -1), 14, false, "-0"},
+		{1, 14, false, "1"},
+		{-1.5, 14, false, "-1.5"},
+		{100, 14, false, "100"},
+		{0.5, 14, false, "0.5"},
+		{0.0001, 14, false, "0.0001"},
+		{0.00001, 14, false, "1.0E-5"},
+		{0.000012345, 14, false, "1.2345E-5"},
+		{1e14, 14, false, "1.0E+14"},
+		{99999999999999, 14, false, "99999999999999"},
+		{1e25, 14, false, "1.0E+25"},
+		{-1e25, 14, false, "-1.0E+25"},
+		{1.5e300, 14, false, "1.5E+300"},
+		{123456789123456.40, 14, false, "1.2345678912346E+14"},
+		{123.4567890123456, 14, false, "123.45678901235"},
+		{0.30000000000000004, 14, false, "0.3"},
+		{0.30000000000000004, 17, false, "0.30000000000000004"},
+		{0.30000000000000004, -1, false, "0.30000000000000004"},
+		{0.1, 17, false, "0.10000000000000001"},
+		{0.1, -1, false, "0.1"},
+		{1e15, -1, false, "1000000000000000"},
+		{1e17, -1, false, "1.0E+17"},
+		{123456789012345680, -1, false, "1.2345678901234568E+17"},
+		{1.5, 0, false, "2"},
+		{1.5, 1, false, "2"},
+		{12345, 3, false, "1.23E+4"},
+		{1, -1, true, "1.0"},
+		{-0.0, 17, true, "0.0"},
+		{1.5, 17, true, "1.5"},
+		{1e25, 17, true, "1.0000000000000001E+25"},
+		{1e25, -1, true, "1.0E+25"},
+		{math.Inf(1), 17, true, "INF"},
+		{math.Inf(-1), 17, false, "-INF"},
+		{math.NaN(), 17, true, "NAN"},
+	}
+
+	for _, tc := range cases {
+		result := string(smartStrAppendDouble(nil, tc.value, tc.precision, tc.zeroFraction))
+		if result != tc.expected {
+			t.Errorf("smartStrAppendDouble(%v, %d, %v) = %q; want %q", tc.value, tc.precision, tc.zeroFraction, result, tc.expected)
+		}
+	}
+}